@@ -0,0 +1,209 @@
+package fheap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// PopAtOrAbove removes every element whose priority is at least as high as
+// the threshold from the heap, and returns them in priority order. The
+// heap is consolidated once.
+func (fh *Heap[V, P]) PopAtOrAbove(threshold P) (items []Item[V, P], err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return nil, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	matches := fh.collectTop(func(x *fnode[V, P]) bool {
+		return !fh.higherThan(threshold, x.priority)
+	})
+	slices.SortFunc(matches, fh.compareNodes)
+	items = make([]Item[V, P], len(matches))
+	for i, x := range matches {
+		items[i] = Item[V, P]{x.value, x.priority}
+	}
+	if err := fh.extract(matches); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// PopTies removes every element tied for the highest priority from the
+// heap, and returns their values. The heap is consolidated once.
+func (fh *Heap[V, P]) PopTies() (values []V, err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return nil, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.prioritaire == nil {
+		return nil, ErrEmptyHeap
+	}
+	highest := fh.prioritaire.priority
+	matches := fh.collectTop(func(x *fnode[V, P]) bool {
+		return fh.prioritiesEqual(x.priority, highest)
+	})
+	slices.SortFunc(matches, fh.compareNodes)
+	values = make([]V, len(matches))
+	for i, x := range matches {
+		values[i] = x.value
+	}
+	if err := fh.extract(matches); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// DeleteWhere deletes every element for which `pred` holds from the heap,
+// returning how many were deleted. Matching nodes are cut from the heap
+// directly, so no priority needs to be reserved, and the heap is
+// consolidated once.
+func (fh *Heap[V, P]) DeleteWhere(pred func(value V, priority P) bool) (n int, err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return 0, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return 0, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	var matches []*fnode[V, P]
+	for x := range fh.nodes() {
+		var match bool
+		fh.callback(func() { match = pred(x.value, x.priority) })
+		if match {
+			matches = append(matches, x)
+		}
+	}
+	if err := fh.extract(matches); err != nil {
+		return 0, err
+	}
+	return len(matches), nil
+}
+
+// collectTop returns the nodes matching a predicate which holds for a
+// node's ancestors whenever it holds for the node, such as a priority
+// threshold. Only the matching nodes and their children are visited.
+func (fh *Heap[V, P]) collectTop(match func(x *fnode[V, P]) bool) []*fnode[V, P] {
+	var matches []*fnode[V, P]
+	if fh.prioritaire == nil {
+		return matches
+	}
+	stack := []*fnode[V, P]{fh.prioritaire}
+	for len(stack) > 0 {
+		start := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for x := start; ; x = x.right {
+			if match(x) {
+				matches = append(matches, x)
+				if x.children != nil {
+					stack = append(stack, x.children)
+				}
+			}
+			if x.right == start {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// compareNodes compares nodes by priority for sorting, highest first,
+// breaking ties by insertion order.
+func (fh *Heap[V, P]) compareNodes(x, y *fnode[V, P]) int {
+	switch {
+	case fh.nodeHigherThan(x, y):
+		return -1
+	case fh.nodeHigherThan(y, x):
+		return 1
+	}
+	return cmp.Compare(x.seq, y.seq)
+}
+
+// extract removes the given nodes from the heap. Each node is first cut
+// from its parent, after which the nodes' children join the root list, and
+// the heap is consolidated once. The nodes are left without links, so their
+// handles are known to be stale.
+func (fh *Heap[V, P]) extract(nodes []*fnode[V, P]) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	extracted := make(map[*fnode[V, P]]bool, len(nodes))
+	for _, x := range nodes {
+		extracted[x] = true
+	}
+	for _, x := range nodes {
+		if y := x.parent; y != nil {
+			if err := fh.cut(x, y); err != nil {
+				return err
+			}
+			if err := fh.cascadingCut(y); err != nil {
+				return err
+			}
+		}
+	}
+	var roots []*fnode[V, P]
+	for root := fh.prioritaire; ; {
+		if len(roots) > fh.len() {
+			return fh.corrupt("root list is longer than the heap's %d values", fh.len())
+		}
+		next := root.right
+		if !extracted[root] {
+			roots = append(roots, root)
+		} else if root.children != nil {
+			for child := root.children; ; {
+				if len(roots) > fh.len() {
+					return fh.corrupt("node %v has more children than the heap has values", root.value)
+				}
+				sibling := child.right
+				child.parent = nil
+				fh.unmark(child)
+				roots = append(roots, child)
+				if child = sibling; child == root.children {
+					break
+				}
+			}
+		}
+		if root = next; root == fh.prioritaire {
+			break
+		}
+	}
+	for _, x := range nodes {
+		fh.unindex(x)
+		fh.changes.record(x.value, true)
+		x.left, x.right, x.children, x.degree = nil, nil, nil, 0
+	}
+	fh.observeSize()
+	fh.prioritaire = nil
+	for _, root := range roots {
+		root.left, root.right = root, root
+		if fh.prioritaire == nil {
+			fh.prioritaire = root
+		} else if err := fh.prioritaire.insertLeft(root); err != nil {
+			return err
+		}
+	}
+	if fh.prioritaire == nil {
+		fh.maxDegree = 0
+		return nil
+	}
+	if err := fh.consolidate(); err != nil {
+		return err
+	}
+	if fh.relaxation != nil {
+		fh.relaxation.bound = fh.prioritaire.priority
+	}
+	return nil
+}
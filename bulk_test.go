@@ -0,0 +1,144 @@
+package fheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// scrambledHeap returns a heap of the values [1, n], whose trees have been
+// consolidated before increasing some of their priorities.
+func scrambledHeap(t *testing.T, n int) *Heap[int, int] {
+	t.Helper()
+	h := intMinHeap[int]()
+	for _, p := range rand.Perm(n + 1) {
+		if err := Push(h, p, p+n, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range rand.Perm(n + 1)[:n/2] {
+		if v == 0 {
+			continue
+		}
+		if err := IncreasePriority(h, v, v, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return h
+}
+
+func TestFHeapPopAtOrAbove(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	threshold := N + N/2
+	items, err := h.PopAtOrAbove(threshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range items {
+		if item.Priority > threshold {
+			t.Fatalf("popped %v below the threshold", item)
+		}
+		if i > 0 && item.Priority < items[i-1].Priority {
+			t.Fatalf("popped %v after %v", item, items[i-1])
+		}
+	}
+	if len(items)+h.Len() != N {
+		t.Fatalf("expected %d values in total, got %d", N, len(items)+h.Len())
+	}
+	for h.Len() > 0 {
+		_, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p <= threshold {
+			t.Fatalf("left priority %d at or above the threshold", p)
+		}
+	}
+	if len(items) == 0 {
+		t.Fatal("expected some items at or above the threshold")
+	}
+}
+
+func TestFHeapPopTies(t *testing.T) {
+	h := New[int, Level](HigherLevel, levelReserved, WithFIFOTies[int, Level]())
+	if _, err := h.PopTies(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	for v := 0; v < N; v++ {
+		if err := Push(h, v, Level(1+rand.Intn(4)), t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	popped := 1
+	for level := Critical; level <= Low && h.Len() > 0; level++ {
+		_, highest, err := h.Peek()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values, err := h.PopTies()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range values {
+			if i > 0 && v < values[i-1] {
+				t.Fatalf("popped %d after %d", v, values[i-1])
+			}
+		}
+		if _, p, err := h.Peek(); err == nil && p == highest {
+			t.Fatalf("left a value with priority %v", highest)
+		}
+		popped += len(values)
+	}
+	if popped != N || h.Len() != 0 {
+		t.Fatalf("expected %d values popped, got %d", N, popped)
+	}
+}
+
+func TestFHeapDeleteWhere(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	n, err := h.DeleteWhere(func(v, _ int) bool { return v%3 == 0 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != N/3 {
+		t.Fatalf("expected %d deletions, got %d", N/3, n)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	prev := 0
+	for h.Len() > 0 {
+		v, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v%3 == 0 {
+			t.Fatalf("expected %d to be deleted", v)
+		}
+		if p < prev {
+			t.Fatalf("popped priority %d after %d", p, prev)
+		}
+		prev = p
+	}
+	unreserved := NewUnreserved[int, int](func(x, y int) bool { return x < y })
+	if err := unreserved.Push(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := unreserved.DeleteWhere(func(int, int) bool { return true }); err != nil || n != 1 {
+		t.Fatalf("expected (1, nil), got (%d, %v)", n, err)
+	}
+}
@@ -0,0 +1,67 @@
+package fheap
+
+import "errors"
+
+var ErrHeapFull = errors.New("heap is full")
+
+// OverflowPolicy determines what happens when a value is pushed onto a
+// heap holding its maximum number of values.
+type OverflowPolicy int
+
+const (
+	// OverflowReject makes pushes fail with ErrHeapFull.
+	OverflowReject OverflowPolicy = iota
+	// OverflowEvictWorst makes pushes evict the lowest-priority values to
+	// admit the new ones, so that the heap keeps the highest-priority
+	// values pushed. A new value whose priority is lowest is dropped.
+	OverflowEvictWorst
+)
+
+// WithMaxSize bounds the number of values the heap holds to `n`, handling
+// pushes beyond it according to `policy`.
+func WithMaxSize[V comparable, P any](n int, policy OverflowPolicy) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.capacity = &capacity[V, P]{max: max(n, 0), overflow: policy}
+	}
+}
+
+// capacity is a heap's size bound. Values evicted on overflow are reported
+// to `onEvict` if it isn't nil.
+type capacity[V comparable, P any] struct {
+	max      int
+	overflow OverflowPolicy
+	onEvict  func(value V, priority P)
+}
+
+// admit returns ErrHeapFull if the heap rejects overflowing pushes and
+// can't hold `n` more values.
+func (fh *Heap[V, P]) admit(n int) error {
+	c := fh.capacity
+	if c == nil || c.overflow != OverflowReject || fh.len()+n <= c.max {
+		return nil
+	}
+	return ErrHeapFull
+}
+
+// trim evicts the lowest-priority values if the heap evicts on overflow and
+// holds more values than it can. Values are extracted at once, so that the
+// heap is consolidated once.
+func (fh *Heap[V, P]) trim() error {
+	c := fh.capacity
+	if c == nil || c.overflow != OverflowEvictWorst || fh.len() <= c.max {
+		return nil
+	}
+	lowest, err := fh.lowest(fh.len() - c.max)
+	if err != nil {
+		return err
+	}
+	if err := fh.extract(lowest); err != nil {
+		return err
+	}
+	if c.onEvict != nil {
+		for _, x := range lowest {
+			fh.callback(func() { c.onEvict(x.value, x.priority) })
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapMaxSizeReject(t *testing.T) {
+	const bound = 8
+	h := New(func(x, y int) bool { return x < y }, math.MinInt, WithMaxSize[int, int](bound, OverflowReject))
+	for v := range bound {
+		if err := Push(h, v, v, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Push(bound, -1); err != ErrHeapFull {
+		t.Fatalf("expected %v, got %v", ErrHeapFull, err)
+	}
+	if err := h.PushAll([]Item[int, int]{{bound, 0}}); !errors.Is(err, ErrHeapFull) {
+		t.Fatalf("expected %v, got %v", ErrHeapFull, err)
+	}
+	if err := h.PushSet(0, bound); err != ErrHeapFull {
+		t.Fatalf("expected %v, got %v", ErrHeapFull, err)
+	}
+	other := intMinHeap[int]()
+	if err := other.Push(bound, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Meld(other); err != ErrHeapFull {
+		t.Fatalf("expected %v, got %v", ErrHeapFull, err)
+	}
+	// pushing a value already in the heap doesn't grow it
+	if err := h.PushAll([]Item[int, int]{{0, 0}}); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := Push(h, bound, -1, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if n := h.Len(); n != bound {
+		t.Fatalf("expected size=%d, got %d", bound, n)
+	}
+}
+
+func TestFHeapMaxSizeEvictWorst(t *testing.T) {
+	N := *HeapSize
+	bound := N / 10
+	// a top-N heap of the lowest priorities, which reserves no priority
+	h := NewUnreserved[int](func(x, y int) bool { return x < y }, WithMaxSize[int, int](bound, OverflowEvictWorst))
+	perm := rand.Perm(N)
+	for i, p := range perm {
+		switch i % 3 {
+		case 0:
+			if err := Push(h, p, p, t.Name()); err != nil {
+				t.Fatal(err)
+			}
+		case 1:
+			handle, err := h.PushHandle(p, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if handle.Stale() == h.Contains(p) {
+				t.Fatalf("handle to %d is stale=%t, but the heap contains it=%t", p, handle.Stale(), h.Contains(p))
+			}
+		default:
+			if err := h.PushAll([]Item[int, int]{{p, p}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if n := h.Len(); n > bound {
+			t.Fatalf("expected size<=%d, got %d", bound, n)
+		}
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	for expected := range bound {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package fheap
+
+import "errors"
+
+// CheckedHeap wraps a heap, validating its invariants after every
+// operation. Violations are reported as errors wrapping ErrCorrupted.
+type CheckedHeap[V comparable, P any] struct {
+	inner *Heap[V, P]
+}
+
+// NewChecked wraps a heap in a CheckedHeap.
+func NewChecked[V comparable, P any](inner *Heap[V, P]) *CheckedHeap[V, P] {
+	return &CheckedHeap[V, P]{inner: inner}
+}
+
+// Unwrap returns the wrapped heap.
+func (ch *CheckedHeap[V, P]) Unwrap() *Heap[V, P] {
+	return ch.inner
+}
+
+// Size returns the number of elements in the heap.
+func (ch *CheckedHeap[V, P]) Size() (int, error) {
+	return ch.inner.Size()
+}
+
+// TryPeek returns the highest-priority value in the heap without removing
+// it, reporting whether there was one.
+func (ch *CheckedHeap[V, P]) TryPeek() (V, bool) {
+	return ch.inner.TryPeek()
+}
+
+// Push inserts a given value with the supplied priority into the heap.
+func (ch *CheckedHeap[V, P]) Push(value V, priority P) error {
+	return ch.check(ch.inner.Push(value, priority))
+}
+
+// Pop removes and returns the highest-priority element from the heap.
+func (ch *CheckedHeap[V, P]) Pop() (V, error) {
+	value, err := ch.inner.Pop()
+	return value, ch.check(err)
+}
+
+// IncreasePriority increases a value's priority in the heap, if present.
+func (ch *CheckedHeap[V, P]) IncreasePriority(value V, priority P) error {
+	return ch.check(ch.inner.IncreasePriority(value, priority))
+}
+
+// IncreasePriorityPrev increases a value's priority in the heap, if present,
+// returning the value's priority prior to the increase.
+func (ch *CheckedHeap[V, P]) IncreasePriorityPrev(value V, priority P) (P, error) {
+	prev, err := ch.inner.IncreasePriorityPrev(value, priority)
+	return prev, ch.check(err)
+}
+
+// Delete deletes a value from the heap, if present.
+func (ch *CheckedHeap[V, P]) Delete(value V) error {
+	return ch.check(ch.inner.Delete(value))
+}
+
+// check validates the wrapped heap's invariants following an operation
+// which returned err, as by Validate, which reports a heap already flagged
+// as corrupted without walking it, and recovers from the comparator
+// panicking during validation. Errors wrapping ErrCorrupted are returned
+// as is.
+func (ch *CheckedHeap[V, P]) check(err error) error {
+	if ch.inner == nil || errors.Is(err, ErrCorrupted) {
+		return err
+	}
+	if violation := ch.inner.Validate(); violation != nil {
+		return errors.Join(err, violation)
+	}
+	return err
+}
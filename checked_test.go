@@ -0,0 +1,80 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCheckedHeap(t *testing.T) {
+	h := NewChecked(intMinHeap[int]())
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.IncreasePriority(N-1, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Delete(N / 2); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	} else if v != N-1 {
+		t.Fatalf("expected value=%d, got %d", N-1, v)
+	}
+	if _, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if debug {
+		t.Skip("corrupting the heap panics under fheapdebug")
+	}
+	for _, n := range h.Unwrap().values {
+		if n.parent == nil && n != h.Unwrap().prioritaire {
+			n.bereaved = true
+			break
+		}
+	}
+	if err := h.Push(N, N); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("expected %v, got %v", ErrCorrupted, err)
+	}
+	nilChecked := NewChecked[int, int](nil)
+	if _, err := nilChecked.Pop(); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+}
+
+func TestCheckedHeap_ComparatorPanic(t *testing.T) {
+	if debug {
+		t.Skip("invariant assertions under fheapdebug call the comparator")
+	}
+	broken := false
+	h := NewChecked(New[int](func(x, y int) bool {
+		if broken {
+			panic("broken comparator")
+		}
+		return x < y
+	}, math.MinInt))
+	for v := range 3 {
+		if err := h.Push(v, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	broken = true
+	// Delete fails without comparing, but validating the heap compares
+	var panicErr *ComparatorPanicError[int]
+	if err := h.Delete(3); !errors.As(err, &panicErr) || !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected the comparator's panic as an error, got %v", err)
+	}
+	corrupted := h.Unwrap().corrupted
+	if !errors.Is(corrupted, ErrCorrupted) {
+		t.Fatalf("expected the heap to be flagged as corrupted, got %v", corrupted)
+	}
+	// a heap flagged as corrupted isn't validated again
+	if err := h.Push(3, 3); err != corrupted {
+		t.Fatalf("expected %v, got %v", corrupted, err)
+	}
+}
@@ -0,0 +1,119 @@
+package fheap
+
+import "maps"
+
+// WithValueCopier sets the function used to copy values when the heap is
+// copied, e.g. by Clone. By default, values are copied by assignment.
+func WithValueCopier[V comparable, P any](copyValue func(value V) V) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.copyValue = copyValue
+	}
+}
+
+// WithPriorityCopier sets the function used to copy priorities when the
+// heap is copied, e.g. by Clone. By default, priorities are copied by
+// assignment.
+func WithPriorityCopier[V comparable, P any](copyPriority func(priority P) P) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.copyPriority = copyPriority
+	}
+}
+
+// Clone returns a copy of the heap, with the same configuration and tree
+// structure. Values and priorities are copied using the heap's copiers.
+func (fh *Heap[V, P]) Clone() (*Heap[V, P], error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return nil, fh.corrupted
+	}
+	clone := *fh
+	clone.higherThan = fh.comparator()
+	clone.newIndex(fh.len())
+	clone.peak = fh.len()
+	clone.scratch = nil
+	if fh.diagnostics != nil {
+		clone.diagnostics = &diagnostics[V, P]{report: fh.diagnostics.report}
+	}
+	if fh.relaxation != nil {
+		r := *fh.relaxation
+		clone.relaxation = &r
+	}
+	if fh.changes != nil {
+		c := *fh.changes
+		c.changes = maps.Clone(fh.changes.changes)
+		clone.changes = &c
+	}
+	if fh.memory != nil {
+		m := *fh.memory
+		clone.memory = &m
+	}
+	if fh.watermarks != nil {
+		w := *fh.watermarks
+		clone.watermarks = &w
+	}
+	var err error
+	if clone.prioritaire, err = clone.copyRing(fh.prioritaire); err != nil {
+		return nil, err
+	}
+	if debug {
+		clone.assertInvariants()
+	}
+	return &clone, nil
+}
+
+// copyRing copies a ring of roots and their descendants, indexing the
+// copies by their values, and returns the copy of start. Child rings wait
+// on an explicit stack, so that tall trees can't exhaust the goroutine's
+// stack.
+func (fh *Heap[V, P]) copyRing(start *fnode[V, P]) (*fnode[V, P], error) {
+	if start == nil {
+		return nil, nil
+	}
+	type ring struct{ start, parent *fnode[V, P] }
+	var first *fnode[V, P]
+	stack := []ring{{start: start}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		var head *fnode[V, P]
+		for n := r.start; ; n = n.right {
+			value, priority := n.value, n.priority
+			if fh.copyValue != nil {
+				value = fh.copyValue(value)
+			}
+			if fh.copyPriority != nil {
+				priority = fh.copyPriority(priority)
+			}
+			c := newFnode(value, priority)
+			c.owner = fh
+			c.bereaved = n.bereaved
+			c.degree = n.degree
+			c.seq = n.seq
+			if n.extras != nil {
+				extras := *n.extras
+				c.extras = &extras
+			}
+			c.parent = r.parent
+			if n.children != nil {
+				stack = append(stack, ring{n.children, c})
+			}
+			fh.index(value, c)
+			if head == nil {
+				head = c
+			} else if err := head.insertLeft(c); err != nil {
+				return nil, err
+			}
+			if n.right == r.start {
+				break
+			}
+		}
+		if r.parent == nil {
+			first = head
+		} else {
+			r.parent.children = head
+		}
+	}
+	return first, nil
+}
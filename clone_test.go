@@ -0,0 +1,63 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapClone(t *testing.T) {
+	type deadline struct{ at int }
+	copies := 0
+	h := New[int, *deadline](func(x, y *deadline) bool { return x.at < y.at }, &deadline{math.MinInt},
+		WithPriorityCopier[int](func(p *deadline) *deadline {
+			copies++
+			c := *p
+			return &c
+		}))
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, &deadline{p}, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < N/4; i++ {
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := IncreasePriority(h, N-1, &deadline{-1}, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := h.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copies != len(h.values) {
+		t.Fatalf("expected %d priority copies, got %d", len(h.values), copies)
+	}
+	if err := isFibonacciHeap(clone); err != nil {
+		t.Fatal(err)
+	}
+	for v, n := range clone.values {
+		if n.priority == h.values[v].priority {
+			t.Fatalf("priority of value %d wasn't copied", v)
+		}
+	}
+	for {
+		expected, err := h.Pop()
+		if err == ErrEmptyHeap {
+			break
+		}
+		actual, err := Pop(clone, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Fatalf("expected value=%d, got %d", expected, actual)
+		}
+	}
+	if _, err := clone.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+}
@@ -0,0 +1,140 @@
+package fheap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Codec holds the functions used to serialise a heap's values and
+// priorities.
+type Codec[V, P any] struct {
+	MarshalValue      func(value V) ([]byte, error)
+	UnmarshalValue    func(data []byte) (V, error)
+	MarshalPriority   func(priority P) ([]byte, error)
+	UnmarshalPriority func(data []byte) (P, error)
+}
+
+var errNoCodec = fmt.Errorf("%w: no codec", ErrUnsupported)
+var errTruncated = errors.New("truncated data")
+
+// WithCodec sets the codec used to serialise the heap's values and
+// priorities, enabling MarshalBinary and UnmarshalBinary.
+func WithCodec[V comparable, P any](codec Codec[V, P]) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.codec = &codec
+	}
+}
+
+// MarshalBinary encodes the heap's values and their priorities using the
+// heap's codec. The heap's structure isn't encoded.
+func (fh *Heap[V, P]) MarshalBinary() ([]byte, error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.codec == nil {
+		return nil, errNoCodec
+	}
+	data := binary.AppendUvarint(nil, uint64(fh.len()))
+	for node := range fh.snapshot() {
+		v, err := fh.codec.MarshalValue(node.value)
+		if err != nil {
+			return nil, err
+		}
+		p, err := fh.codec.MarshalPriority(node.priority)
+		if err != nil {
+			return nil, err
+		}
+		data = binary.AppendUvarint(data, uint64(len(v)))
+		data = append(data, v...)
+		data = binary.AppendUvarint(data, uint64(len(p)))
+		data = append(data, p...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the heap's contents with values and priorities
+// decoded from data using the heap's codec. The values are pushed into an
+// empty heap with the same configuration, which is melded into the heap
+// once its contents are cleared, so that the heap is left unmodified if
+// decoding or pushing fails.
+func (fh *Heap[V, P]) UnmarshalBinary(data []byte) error {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.codec == nil {
+		return errNoCodec
+	}
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return err
+	}
+	// every entry takes at least one byte, so the count can't be trusted
+	// to size the slices beyond the data's length
+	size := int(min(n, uint64(len(data))))
+	values := make([]V, 0, size)
+	priorities := make([]P, 0, size)
+	for ; n > 0; n-- {
+		var v, p []byte
+		if v, data, err = readChunk(data); err != nil {
+			return err
+		}
+		if p, data, err = readChunk(data); err != nil {
+			return err
+		}
+		value, err := fh.codec.UnmarshalValue(v)
+		if err != nil {
+			return err
+		}
+		priority, err := fh.codec.UnmarshalPriority(p)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+		priorities = append(priorities, priority)
+	}
+	// the heap's watermarks and diagnostics only observe the meld
+	decoded := fh.emptyCopy()
+	decoded.watermarks, decoded.diagnostics = nil, nil
+	decoded.newIndex(len(values))
+	for i, value := range values {
+		if err := decoded.Push(value, priorities[i]); err != nil {
+			return err
+		}
+	}
+	for node := range fh.nodes() {
+		fh.changes.record(node.value, true)
+		node.left, node.right, node.twin = nil, nil, nil
+	}
+	fh.prioritaire = nil
+	fh.maxDegree = 0
+	fh.newIndex(decoded.len())
+	fh.peak = decoded.len()
+	fh.marked = 0
+	return fh.Meld(decoded)
+}
+
+// readUvarint reads a uvarint from the start of data, returning the rest.
+func readUvarint(data []byte) (uint64, []byte, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, errTruncated
+	}
+	return x, data[n:], nil
+}
+
+// readChunk reads a length-prefixed chunk from the start of data,
+// returning the rest.
+func readChunk(data []byte) ([]byte, []byte, error) {
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(data)) < n {
+		return nil, nil, errTruncated
+	}
+	return data[:n], data[n:], nil
+}
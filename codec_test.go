@@ -0,0 +1,86 @@
+package fheap
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+var stringCodec = Codec[string, int]{
+	MarshalValue:   func(v string) ([]byte, error) { return []byte(v), nil },
+	UnmarshalValue: func(b []byte) (string, error) { return string(b), nil },
+	MarshalPriority: func(p int) ([]byte, error) {
+		return strconv.AppendInt(nil, int64(p), 10), nil
+	},
+	UnmarshalPriority: func(b []byte) (int, error) { return strconv.Atoi(string(b)) },
+}
+
+func TestFHeapMarshalBinary(t *testing.T) {
+	codec := stringCodec
+	higherThan := func(x, y int) bool { return x < y }
+	h := New(higherThan, math.MinInt, WithCodec(codec))
+	N := *HeapSize
+	for i := 0; i < N; i++ {
+		if err := Push(h, strconv.Itoa(i), N-i, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := New(higherThan, math.MinInt, WithCodec(codec))
+	if err := restored.Push("stale", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(restored); err != nil {
+		t.Fatal(err)
+	}
+	for i := N - 2; i >= 0; i-- {
+		if v, err := Pop(restored, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != strconv.Itoa(i) {
+			t.Fatalf("expected value=%d, got %s", i, v)
+		}
+	}
+	if _, err := restored.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("expected truncated data error")
+	}
+	if _, err := intMinHeap[int]().MarshalBinary(); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+}
+
+func TestFHeapUnmarshalBinary_Invalid(t *testing.T) {
+	h := New(func(x, y int) bool { return x < y }, math.MinInt, WithCodec(stringCodec))
+	if err := h.Push("kept", 0); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{
+		// a count far beyond the data mustn't be used to size allocations
+		"count": binary.AppendUvarint(nil, math.MaxInt64),
+		// a repeated value is only rejected once half the payload is pushed
+		"duplicate": {2, 1, 'a', 1, '1', 1, 'a', 1, '2'},
+	} {
+		if err := h.UnmarshalBinary(data); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+		if size, _ := h.Size(); size != 1 || !h.Contains("kept") {
+			t.Fatalf("%s: expected the heap to be left unmodified, got size=%d", name, size)
+		}
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
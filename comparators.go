@@ -0,0 +1,131 @@
+package fheap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NewCmp creates an empty Fibonacci heap ordering priorities with a
+// three-way comparison function, such as cmp.Compare: priorities which
+// compare negative are popped first. Priorities comparing zero are equal.
+// Its other arguments are as for New.
+// NewCmp panics with ErrNilComparator if `compare` is nil.
+func NewCmp[V comparable, P any](compare func(a, b P) int, highestPriority P, opts ...Option[V, P]) *Heap[V, P] {
+	if compare == nil {
+		panic(ErrNilComparator)
+	}
+	higherThan := func(x, y P) bool { return compare(x, y) < 0 }
+	equal := func(a, b P) bool { return compare(a, b) == 0 }
+	return New(higherThan, highestPriority, append([]Option[V, P]{WithPriorityEqual[V](equal)}, opts...)...)
+}
+
+// WithComparablePriorities makes the heap test priorities for equality
+// with ==, rather than by comparing them both ways with `higherThan`, which
+// spares expensive comparison functions two calls per Push and priority
+// increase to recognise the reserved priority. It's equivalent to
+// WithPriorityEqual with ==, so it mustn't be used if == disagrees with
+// `higherThan`, e.g. for floating-point priorities reserving NaN.
+func WithComparablePriorities[V, P comparable]() Option[V, P] {
+	return WithPriorityEqual[V](func(a, b P) bool { return a == b })
+}
+
+// Reverse returns a priority comparison function ordering priorities the
+// opposite way to `higherThan`, turning a min-heap into a max-heap and
+// vice versa.
+func Reverse[P any](higherThan func(x, y P) bool) func(x, y P) bool {
+	return func(x, y P) bool {
+		return higherThan(y, x)
+	}
+}
+
+// Then returns a priority comparison function ordering priorities by
+// `primary`, and priorities neither of which is higher than the other by
+// `secondary`.
+func Then[P any](primary, secondary func(x, y P) bool) func(x, y P) bool {
+	return func(x, y P) bool {
+		if primary(x, y) {
+			return true
+		}
+		return !primary(y, x) && secondary(x, y)
+	}
+}
+
+var ErrInconsistentComparator = errors.New("inconsistent priority comparison function")
+
+// WithComparatorChecks makes the heap check every `every`-th comparison of
+// priorities for consistency, for debugging. A comparison function must
+// be irreflexive, asymmetric and transitive, which is checked against the
+// priorities compared and those of recently checked comparisons. Violations
+// are returned as a *ComparatorPanicError wrapping an error wrapping
+// ErrInconsistentComparator, which flags the heap as corrupted.
+func WithComparatorChecks[V comparable, P any](every int) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.unchecked, fh.checkEvery = fh.higherThan, max(every, 1)
+		fh.higherThan = fh.comparator()
+	}
+}
+
+// comparator returns the heap's comparison function for a copy of the
+// heap. Checked comparisons get a fresh checker, whose sampling state isn't
+// shared with the heap's.
+func (fh *Heap[V, P]) comparator() func(x, y P) bool {
+	if fh.unchecked == nil {
+		return fh.higherThan
+	}
+	c := &comparatorChecker[P]{higherThan: fh.unchecked, every: fh.checkEvery}
+	return c.compare
+}
+
+// comparatorChecker samples a comparison function's calls to check its
+// consistency.
+type comparatorChecker[P any] struct {
+	higherThan func(x, y P) bool
+	every      int
+	calls      int
+	recent     []P
+}
+
+// compare compares two priorities, checking the comparison if sampled.
+func (c *comparatorChecker[P]) compare(x, y P) bool {
+	higher := c.higherThan(x, y)
+	if c.calls++; c.calls%c.every != 0 {
+		return higher
+	}
+	if err := c.check(x, y, higher); err != nil {
+		panic(&ComparatorPanicError[P]{X: x, Y: y, Value: err})
+	}
+	if len(c.recent) < comparatorCheckWindow {
+		c.recent = append(c.recent, x)
+	} else {
+		c.recent[c.calls/c.every%comparatorCheckWindow] = x
+	}
+	return higher
+}
+
+// comparatorCheckWindow is the number of recently checked priorities
+// against which transitivity is checked.
+const comparatorCheckWindow = 4
+
+// check checks the comparison of x and y, whose result was `higher`.
+func (c *comparatorChecker[P]) check(x, y P, higher bool) error {
+	if c.higherThan(x, x) {
+		return fmt.Errorf("%w: %v is higher than itself", ErrInconsistentComparator, x)
+	}
+	if higher && c.higherThan(y, x) {
+		return fmt.Errorf("%w: %v and %v are each higher than the other", ErrInconsistentComparator, x, y)
+	}
+	a, b := x, y
+	if !higher {
+		if !c.higherThan(y, x) {
+			return nil
+		}
+		a, b = y, x
+	}
+	for _, z := range c.recent {
+		if c.higherThan(b, z) && !c.higherThan(a, z) {
+			return fmt.Errorf("%w: %v is higher than %v, which is higher than %v, but not transitively",
+				ErrInconsistentComparator, a, b, z)
+		}
+	}
+	return nil
+}
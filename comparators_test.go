@@ -0,0 +1,213 @@
+package fheap
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewCmp(t *testing.T) {
+	h := NewCmp[int](cmp.Compare[int], math.MinInt)
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Push(-1, math.MinInt); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := h.Delete(N / 2); err != nil {
+		t.Fatal(err)
+	}
+	for expected := 0; expected < N; expected++ {
+		if expected == N/2 {
+			continue
+		}
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+	// priorities equal up to case are popped first in, first out
+	folded := NewCmp[int](func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) }, "",
+		WithFIFOTies[int, string]())
+	for v, p := range []string{"b", "A", "B", "a"} {
+		if err := folded.Push(v, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, expected := range []int{1, 3, 0, 2} {
+		if v, err := folded.Pop(); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
+
+func TestComparatorCombinators(t *testing.T) {
+	type job struct {
+		priority int
+		created  int
+	}
+	byPriority := Reverse(func(x, y job) bool { return x.priority < y.priority })
+	byCreation := func(x, y job) bool { return x.created < y.created }
+	h := NewUnreserved[int](Then(byPriority, byCreation))
+	N := *HeapSize
+	const levels = 4
+	for _, v := range rand.Perm(N) {
+		if err := Push(h, v, job{v % levels, v}, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prev := job{levels, -1}
+	for h.Len() > 0 {
+		_, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.priority > prev.priority || p.priority == prev.priority && p.created < prev.created {
+			t.Fatalf("popped %+v after %+v", p, prev)
+		}
+		prev = p
+	}
+}
+
+func TestComparatorChecks(t *testing.T) {
+	consistent := New(cmp.Less[int], math.MinInt, WithComparatorChecks[int, int](1))
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(consistent, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range N {
+		if _, err := Pop(consistent, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cases := []struct {
+		name       string
+		higherThan func(x, y int) bool
+	}{
+		{"reflexive", func(x, y int) bool { return x <= y }},
+		{"intransitive", func(x, y int) bool { return (y-x+3)%3 == 1 }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := NewUnreserved[int](c.higherThan, WithComparatorChecks[int, int](1))
+			var err error
+			for v := 0; err == nil && v < N; v++ {
+				if err = h.Push(v, v%3); err == nil && v%2 == 1 {
+					_, err = h.Pop()
+				}
+			}
+			if !errors.Is(err, ErrInconsistentComparator) {
+				t.Fatalf("expected %v, got %v", ErrInconsistentComparator, err)
+			}
+			if err := h.Push(-1, 0); !errors.Is(err, ErrCorrupted) {
+				t.Fatalf("expected %v, got %v", ErrCorrupted, err)
+			}
+		})
+	}
+}
+
+func TestComparatorChecks_Copies(t *testing.T) {
+	h := New(cmp.Less[int], math.MinInt, WithComparatorChecks[int, int](1))
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clone, err := h.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	split, err := h.Split(func(v, _ int) bool { return v%2 == 0 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each copy samples its comparisons with its own checker, so that they
+	// can be used concurrently
+	var wg sync.WaitGroup
+	for _, heap := range []*Heap[int, int]{h, clone, split} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range heap.Drain() {
+			}
+		}()
+	}
+	wg.Wait()
+	for _, heap := range []*Heap[int, int]{h, clone, split} {
+		if heap.Len() != 0 {
+			t.Fatalf("expected every copy to be drained, got size=%d", heap.Len())
+		}
+	}
+}
+
+func TestWithComparablePriorities(t *testing.T) {
+	calls := 0
+	lessThan := func(x, y int) bool {
+		calls++
+		return x < y
+	}
+	h := New(lessThan, math.MinInt, WithComparablePriorities[int, int]())
+	if err := h.Push(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	calls = 0
+	if err := h.Push(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	// only the new value is compared with the highest-priority value's,
+	// besides the invariant assertions' comparisons under fheapdebug
+	if calls != 1 && !debug {
+		t.Fatalf("expected 1 comparison, got %d", calls)
+	}
+	if err := h.Push(2, math.MinInt); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := h.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	} else if v != 0 {
+		t.Fatalf("expected 0, got %d", v)
+	}
+}
+
+// slowLess stands in for an expensive comparison function, such as one
+// parsing or collating its priorities.
+func slowLess(x, y int) bool {
+	return fmt.Sprintf("%020d", x) < fmt.Sprintf("%020d", y)
+}
+
+func benchmarkPushExpensive(b *testing.B, opts ...Option[int, int]) {
+	h := New(slowLess, math.MinInt, opts...)
+	perm := rand.Perm(1 << 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range perm {
+			h.Push(p, p)
+		}
+		h.Clear()
+	}
+}
+
+func BenchmarkFHeapPush_ExpensiveComparator(b *testing.B) {
+	benchmarkPushExpensive(b)
+}
+
+func BenchmarkFHeapPush_ExpensiveComparator_Comparable(b *testing.B) {
+	benchmarkPushExpensive(b, WithComparablePriorities[int, int]())
+}
@@ -0,0 +1,49 @@
+package fheap
+
+// Pair is a composite priority ordered lexicographically, i.e. by its first
+// component, then by its second.
+type Pair[P1, P2 any] struct {
+	First  P1
+	Second P2
+}
+
+// Triple is a composite priority ordered lexicographically, i.e. by its
+// first component, then by its second, then by its third.
+type Triple[P1, P2, P3 any] struct {
+	First  P1
+	Second P2
+	Third  P3
+}
+
+// MakePair returns the pair of the given components. The pair of each
+// component's highest priority is the highest pair, for use with New.
+func MakePair[P1, P2 any](first P1, second P2) Pair[P1, P2] {
+	return Pair[P1, P2]{first, second}
+}
+
+// MakeTriple returns the triple of the given components. The triple of each
+// component's highest priority is the highest triple, for use with New.
+func MakeTriple[P1, P2, P3 any](first P1, second P2, third P3) Triple[P1, P2, P3] {
+	return Triple[P1, P2, P3]{first, second, third}
+}
+
+// PairHigherThan returns a comparison function ordering pairs
+// lexicographically, comparing their components with the given functions.
+func PairHigherThan[P1, P2 any](first func(x, y P1) bool, second func(x, y P2) bool) func(x, y Pair[P1, P2]) bool {
+	return Then(
+		func(x, y Pair[P1, P2]) bool { return first(x.First, y.First) },
+		func(x, y Pair[P1, P2]) bool { return second(x.Second, y.Second) },
+	)
+}
+
+// TripleHigherThan returns a comparison function ordering triples
+// lexicographically, comparing their components with the given functions.
+func TripleHigherThan[P1, P2, P3 any](first func(x, y P1) bool, second func(x, y P2) bool, third func(x, y P3) bool) func(x, y Triple[P1, P2, P3]) bool {
+	return Then(
+		Then(
+			func(x, y Triple[P1, P2, P3]) bool { return first(x.First, y.First) },
+			func(x, y Triple[P1, P2, P3]) bool { return second(x.Second, y.Second) },
+		),
+		func(x, y Triple[P1, P2, P3]) bool { return third(x.Third, y.Third) },
+	)
+}
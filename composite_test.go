@@ -0,0 +1,58 @@
+package fheap
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapTriplePriorities(t *testing.T) {
+	type priority = Triple[Level, int, uint64]
+	higherThan := TripleHigherThan(HigherLevel, cmp.Less[int], cmp.Less[uint64])
+	h := New[int](higherThan, MakeTriple(levelReserved, math.MinInt, uint64(0)))
+	if err := h.Push(-1, MakeTriple(levelReserved, math.MinInt, uint64(0))); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	N := *HeapSize
+	levels := []Level{Critical, High, Normal, Low}
+	for i, v := range rand.Perm(N) {
+		p := MakeTriple(levels[v%len(levels)], v%7, uint64(i))
+		if err := Push(h, v, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Delete(N / 2); err != nil {
+		t.Fatal(err)
+	}
+	var prev *priority
+	for h.Len() > 0 {
+		_, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev != nil && higherThan(p, *prev) {
+			t.Fatalf("popped %+v after %+v", p, *prev)
+		}
+		prev = &p
+	}
+}
+
+func TestPairHigherThan(t *testing.T) {
+	higherThan := PairHigherThan(cmp.Less[int], Reverse(cmp.Less[string]))
+	cases := []struct {
+		x, y     Pair[int, string]
+		expected bool
+	}{
+		{MakePair(1, "a"), MakePair(2, "a"), true},
+		{MakePair(2, "a"), MakePair(1, "b"), false},
+		{MakePair(1, "b"), MakePair(1, "a"), true},
+		{MakePair(1, "a"), MakePair(1, "b"), false},
+		{MakePair(1, "a"), MakePair(1, "a"), false},
+	}
+	for _, c := range cases {
+		if got := higherThan(c.x, c.y); got != c.expected {
+			t.Errorf("higherThan(%+v, %+v): expected %t, got %t", c.x, c.y, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !fheapdebug
+
+package fheap
+
+// debug enables per-operation invariant checks.
+const debug = false
@@ -0,0 +1,38 @@
+//go:build fheapdebug
+
+package fheap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFHeapDebugAssertions(t *testing.T) {
+	h := intMinHeap[int]()
+	for i := range 8 {
+		if err := Push(h, i, i, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range h.values {
+		if n.children != nil {
+			n.degree++
+			break
+		}
+	}
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected a violated invariant to panic")
+		}
+		msg := fmt.Sprint(r)
+		if !strings.Contains(msg, "degree=") || !strings.Contains(msg, "heap with") {
+			t.Fatalf("expected a structural dump, got %q", msg)
+		}
+	}()
+	_ = h.Push(8, 8)
+}
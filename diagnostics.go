@@ -0,0 +1,148 @@
+package fheap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// historySize is the number of recent operations kept for diagnostics.
+const historySize = 16
+
+// Diagnostic describes a structural violation detected after a Pop.
+type Diagnostic struct {
+	Problem string   // what was violated
+	Subtree string   // dump of the offending subtree, if any
+	Recent  []string // most recent operations, oldest first
+}
+
+// Error renders the diagnostic.
+func (d *Diagnostic) Error() string {
+	var b strings.Builder
+	b.WriteString(d.Problem)
+	if d.Subtree != "" {
+		fmt.Fprintf(&b, "\nsubtree:\n%s", d.Subtree)
+	}
+	if len(d.Recent) > 0 {
+		fmt.Fprintf(&b, "\nrecent operations:\n  %s", strings.Join(d.Recent, "\n  "))
+	}
+	return b.String()
+}
+
+// WithDiagnostics enables asserting after each Pop that no node's degree
+// exceeds the theoretical bound D(n) = floor(log_phi(n)), and that the count
+// of bereaved nodes is consistent. Violations are passed to `report`.
+func WithDiagnostics[V comparable, P any](report func(*Diagnostic)) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.diagnostics = &diagnostics[V, P]{report: report}
+	}
+}
+
+// operation is an operation performed on the heap.
+type operation[V, P any] struct {
+	name     string
+	value    V
+	priority P
+}
+
+// diagnostics holds a ring buffer of the most recent operations, and the
+// function violations are reported to.
+type diagnostics[V, P any] struct {
+	report  func(*Diagnostic)
+	history [historySize]operation[V, P]
+	next    int
+	count   int
+}
+
+// record adds an operation to the history. Recording is a no-op if
+// diagnostics aren't enabled.
+func (d *diagnostics[V, P]) record(name string, value V, priority P) {
+	if d == nil {
+		return
+	}
+	d.history[d.next] = operation[V, P]{name, value, priority}
+	d.next = (d.next + 1) % historySize
+	if d.count < historySize {
+		d.count++
+	}
+}
+
+// recent renders the recorded operations, oldest first.
+func (d *diagnostics[V, P]) recent() []string {
+	ops := make([]string, 0, d.count)
+	for i := d.count; i > 0; i-- {
+		op := d.history[(d.next-i+historySize)%historySize]
+		ops = append(ops, fmt.Sprintf("%s(v=%v, p=%v)", op.name, op.value, op.priority))
+	}
+	return ops
+}
+
+// fibonacci holds the Fibonacci numbers F(2), F(3), ... representable as
+// ints. F(k+2) is the fewest nodes a tree whose root has degree k holds.
+var fibonacci = func() []int {
+	fib := []int{1, 2}
+	for {
+		a, b := fib[len(fib)-2], fib[len(fib)-1]
+		if a+b < b {
+			return fib
+		}
+		fib = append(fib, a+b)
+	}
+}()
+
+// degreeBound returns the maximum degree D(n) of any node in an n-node
+// Fibonacci heap, namely the largest k for which F(k+2) <= n, which is at
+// most log_phi(n).
+func degreeBound(n int) int {
+	k, found := slices.BinarySearch(fibonacci, n)
+	if found {
+		return k
+	}
+	return max(k-1, 0)
+}
+
+// diagnose checks the heap's degree bound and bereaved node count,
+// reporting the first violation found.
+func (fh *Heap[V, P]) diagnose() {
+	if fh.corrupted != nil || fh.prioritaire == nil && fh.marked == 0 {
+		return
+	}
+	bound := degreeBound(fh.len())
+	marked := 0
+	var offender *fnode[V, P]
+	var visit func(start *fnode[V, P])
+	visit = func(start *fnode[V, P]) {
+		for n := start; ; n = n.right {
+			if n.bereaved {
+				marked++
+			}
+			if offender == nil && n.degree > bound {
+				offender = n
+			}
+			if n.children != nil {
+				visit(n.children)
+			}
+			if n.right == start {
+				break
+			}
+		}
+	}
+	if fh.prioritaire != nil {
+		visit(fh.prioritaire)
+	}
+	d := &Diagnostic{}
+	switch {
+	case offender != nil:
+		d.Problem = fmt.Sprintf("node %v has degree %d, exceeding D(%d)=%d",
+			offender.value, offender.degree, fh.len(), bound)
+		var b strings.Builder
+		dumpTree(&b, offender, 0, map[*fnode[V, P]]bool{})
+		d.Subtree = b.String()
+	case marked != fh.marked:
+		d.Problem = fmt.Sprintf("counted %d bereaved nodes, but expected %d", marked, fh.marked)
+	default:
+		return
+	}
+	d.Recent = fh.diagnostics.recent()
+	fh.diagnostics.report(d)
+}
@@ -0,0 +1,69 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDegreeBound(t *testing.T) {
+	for n, expected := range map[int]int{0: 0, 1: 0, 2: 1, 3: 2, 4: 2, 5: 3, 8: 4, 88: 8, 89: 9, 100: 9} {
+		if actual := degreeBound(n); actual != expected {
+			t.Fatalf("expected D(%d)=%d, got %d", n, expected, actual)
+		}
+	}
+	for n := 2; n < 1_000_000; n = n*3/2 + 1 {
+		if logPhi := int(math.Log(float64(n)) / math.Log(math.Phi)); degreeBound(n) > logPhi {
+			t.Fatalf("expected D(%d) <= log_phi(%d)=%d, got %d", n, n, logPhi, degreeBound(n))
+		}
+	}
+	if k := degreeBound(math.MaxInt); k != len(fibonacci)-1 {
+		t.Fatalf("expected D(MaxInt)=%d, got %d", len(fibonacci)-1, k)
+	}
+}
+
+func TestFHeapDiagnostics(t *testing.T) {
+	var reported *Diagnostic
+	h := New[int, int](func(x, y int) bool { return x < y }, math.MinInt,
+		WithDiagnostics[int, int](func(d *Diagnostic) { reported = d }))
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p+N); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < N/2; i++ {
+		if _, err := h.Pop(); err != nil {
+			t.Fatal(err)
+		}
+		v := N/2 + rand.Intn(N/2)
+		if n, ok := h.values[v]; ok {
+			if err := h.IncreasePriority(v, n.priority-1); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if reported != nil {
+			t.Fatal(reported)
+		}
+	}
+	if debug {
+		t.Skip("corrupting the heap panics under fheapdebug")
+	}
+	h.marked++
+	if _, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if reported == nil {
+		t.Fatal("expected inconsistent bereaved count to be reported")
+	}
+	if !strings.Contains(reported.Problem, "bereaved") {
+		t.Fatalf("unexpected problem %q", reported.Problem)
+	}
+	if len(reported.Recent) != historySize {
+		t.Fatalf("expected %d recent operations, got %d", historySize, len(reported.Recent))
+	}
+	if last := reported.Recent[historySize-1]; !strings.HasPrefix(last, "Pop(") {
+		t.Fatalf("expected last operation to be Pop, got %q", last)
+	}
+}
@@ -0,0 +1,164 @@
+package fheap
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Diff is the change in a heap's contents between two versions: values
+// which were inserted or re-prioritised, with their current priorities, and
+// values which were removed.
+type Diff[V, P any] struct {
+	From, To uint64
+	Upserted []Item[V, P]
+	Removed  []V
+}
+
+// WithChangeTracking makes the heap track changes to its contents, so that
+// DiffSince can produce the changes since a given version.
+func WithChangeTracking[V comparable, P any]() Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.changes = &changeTracker[V]{changes: map[V]change{}}
+	}
+}
+
+// change is the latest change to a value.
+type change struct {
+	version uint64
+	removed bool
+}
+
+// changeTracker records the version at which each value last changed.
+// Changes at or before `pruned` have been discarded.
+type changeTracker[V comparable] struct {
+	version uint64
+	pruned  uint64
+	changes map[V]change
+}
+
+// record records a change to a value, bumping the version. Recording is a
+// no-op if change tracking isn't enabled.
+func (ct *changeTracker[V]) record(value V, removed bool) {
+	if ct == nil {
+		return
+	}
+	ct.version++
+	ct.changes[value] = change{ct.version, removed}
+}
+
+// Version returns the heap's current version, to be passed to DiffSince
+// later. Versions are only tracked for heaps created with
+// WithChangeTracking.
+func (fh *Heap[V, P]) Version() (uint64, error) {
+	if fh == nil {
+		return 0, ErrNilHeap
+	}
+	if fh.changes == nil {
+		return 0, fmt.Errorf("%w: changes aren't tracked", ErrUnsupported)
+	}
+	return fh.changes.version, nil
+}
+
+// DiffSince returns the changes to the heap's contents since the given
+// version.
+func (fh *Heap[V, P]) DiffSince(version uint64) (Diff[V, P], error) {
+	if fh == nil {
+		return Diff[V, P]{}, ErrNilHeap
+	}
+	if fh.changes == nil {
+		return Diff[V, P]{}, fmt.Errorf("%w: changes aren't tracked", ErrUnsupported)
+	}
+	if fh.unindexed {
+		return Diff[V, P]{}, errUnindexed
+	}
+	if fh.duplicates == DuplicatesAllow {
+		return Diff[V, P]{}, errMultiset
+	}
+	if version < fh.changes.pruned {
+		return Diff[V, P]{}, fmt.Errorf("changes since version %d were pruned up to %d", version, fh.changes.pruned)
+	}
+	diff := Diff[V, P]{From: version, To: fh.changes.version}
+	var upserted []*fnode[V, P]
+	for value, c := range fh.changes.changes {
+		if c.version <= version {
+			continue
+		}
+		if c.removed {
+			diff.Removed = append(diff.Removed, value)
+		} else {
+			upserted = append(upserted, fh.values[value])
+		}
+	}
+	if fh.deterministic {
+		// removed values are listed in the order they were removed
+		slices.SortFunc(diff.Removed, func(a, b V) int {
+			return cmp.Compare(fh.changes.changes[a].version, fh.changes.changes[b].version)
+		})
+		slices.SortFunc(upserted, fh.compareNodes)
+	}
+	for _, node := range upserted {
+		diff.Upserted = append(diff.Upserted, Item[V, P]{node.value, node.priority})
+	}
+	return diff, nil
+}
+
+// PruneChanges discards the record of changes at or before the given
+// version, after which diffs can only be produced since later versions.
+func (fh *Heap[V, P]) PruneChanges(version uint64) error {
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.changes == nil {
+		return fmt.Errorf("%w: changes aren't tracked", ErrUnsupported)
+	}
+	for value, c := range fh.changes.changes {
+		if c.version <= version {
+			delete(fh.changes.changes, value)
+		}
+	}
+	if version > fh.changes.pruned {
+		fh.changes.pruned = version
+	}
+	return nil
+}
+
+// ApplyDiff applies a diff produced by another heap's DiffSince, removing
+// its removed values, and inserting or re-prioritising its upserted ones.
+func (fh *Heap[V, P]) ApplyDiff(diff Diff[V, P]) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return errUnindexed
+	}
+	if fh.duplicates == DuplicatesAllow {
+		return errMultiset
+	}
+	for _, value := range diff.Removed {
+		if _, ok := fh.values[value]; !ok {
+			continue
+		}
+		if err := fh.Delete(value); err != nil {
+			return err
+		}
+	}
+	for _, item := range diff.Upserted {
+		if _, ok := fh.values[item.Value]; ok {
+			err = fh.setPriority(item.Value, item.Priority)
+		} else {
+			err = fh.Push(item.Value, item.Priority)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapDiff(t *testing.T) {
+	higherThan := func(x, y int) bool { return x < y }
+	source := New(higherThan, math.MinInt, WithChangeTracking[int, int]())
+	replica := New[int](higherThan, math.MinInt)
+	sync := func(since uint64) uint64 {
+		diff, err := source.DiffSince(since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := replica.ApplyDiff(diff); err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(replica); err != nil {
+			t.Fatal(err)
+		}
+		if len(replica.values) != len(source.values) {
+			t.Fatalf("expected %d values, got %d", len(source.values), len(replica.values))
+		}
+		for v, n := range source.values {
+			if r, ok := replica.values[v]; !ok || r.priority != n.priority {
+				t.Fatalf("value %d out of sync", v)
+			}
+		}
+		return diff.To
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := source.Push(p, p+N); err != nil {
+			t.Fatal(err)
+		}
+	}
+	version := sync(0)
+	for i := 0; i < N/4; i++ {
+		if _, err := source.Pop(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := source.IncreasePriority(N-1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Delete(N - 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Push(N, 1); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := source.DiffSince(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Upserted) != 2 || len(diff.Removed) != N/4+1 {
+		t.Fatalf("expected 2 upserts and %d removals, got %d and %d",
+			N/4+1, len(diff.Upserted), len(diff.Removed))
+	}
+	latest := sync(version)
+	if err := source.PruneChanges(latest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.DiffSince(version); err == nil {
+		t.Fatal("expected pruned version error")
+	}
+	if _, err := replica.DiffSince(0); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+}
@@ -0,0 +1,33 @@
+package fheap
+
+// WithEagerLinking makes Push link the pushed value's tree with trees of
+// equal degree next to it in the root list, up to `links` times per push,
+// as consolidation would. Bursts of pushes then leave a root list of about
+// logarithmic length rather than one tree per value, smoothing the latency
+// of the next Pop at the cost of slower pushes. Since each link removes a
+// root, the heap's amortised bounds are preserved.
+func WithEagerLinking[V comparable, P any](links int) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.eagerLinks = max(links, 0)
+	}
+}
+
+// linkEagerly links a new root with the roots of equal degree to its left,
+// which were pushed before it, up to the heap's number of eager links. The
+// highest-priority root is never linked, since a relaxed heap's may not be
+// its highest-priority root.
+func (fh *Heap[V, P]) linkEagerly(x *fnode[V, P]) error {
+	for range fh.eagerLinks {
+		y := x.left
+		if x == fh.prioritaire || y == fh.prioritaire || y.degree != x.degree {
+			return nil
+		}
+		if fh.nodeHigherThan(y, x) {
+			x, y = y, x
+		}
+		if err := fh.link(y, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
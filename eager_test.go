@@ -0,0 +1,85 @@
+package fheap
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func eagerHeap(links int) *Heap[int, int] {
+	return New(func(x, y int) bool { return x < y }, math.MinInt, WithEagerLinking[int, int](links))
+}
+
+func TestFHeapEagerLinking(t *testing.T) {
+	h := eagerHeap(64)
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats, err := h.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// besides the roots pushed as the highest-priority value, which aren't
+	// linked, the root list is like a binary counter
+	if bound := 4 * bits.Len(uint(N)); stats.Roots > bound {
+		t.Fatalf("expected at most %d roots, got %d", bound, stats.Roots)
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for expected := range N {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
+
+func TestFHeapEagerLinking_Bounded(t *testing.T) {
+	h := eagerHeap(1)
+	for v := range 4 {
+		if err := h.Push(v, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 1 and 2 are linked, whereas 3 would need two links to join them
+	stats, err := h.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Roots != 3 {
+		t.Fatalf("expected 3 roots, got %d", stats.Roots)
+	}
+}
+
+// benchmarkPopAfterBurst pushes a burst of values and pops one, reporting
+// the pop's latency separately.
+func benchmarkPopAfterBurst(b *testing.B, links int) {
+	h := eagerHeap(links)
+	perm := rand.Perm(1 << 12)
+	var popping time.Duration
+	for i := 0; i < b.N; i++ {
+		for _, p := range perm {
+			h.Push(p, p)
+		}
+		start := time.Now()
+		h.Pop()
+		popping += time.Since(start)
+		h.Clear()
+	}
+	b.ReportMetric(float64(popping.Nanoseconds())/float64(b.N), "ns/pop")
+}
+
+func BenchmarkFHeapPopAfterBurst(b *testing.B) {
+	benchmarkPopAfterBurst(b, 0)
+}
+
+func BenchmarkFHeapPopAfterBurst_EagerLinking(b *testing.B) {
+	benchmarkPopAfterBurst(b, 64)
+}
@@ -0,0 +1,23 @@
+package fheap
+
+import "fmt"
+
+// OpError records the operation, value and priority for which a heap
+// operation failed. Priority is the zero value for operations that weren't
+// given one, such as Delete.
+type OpError[V, P any] struct {
+	Op       string // the failed operation, e.g. "Push"
+	Value    V
+	Priority P
+	Err      error // the underlying error, e.g. ErrDuplicateValue
+}
+
+// Error describes the failed operation.
+func (e *OpError[V, P]) Error() string {
+	return fmt.Sprintf("%s %v (priority %v): %v", e.Op, e.Value, e.Priority, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *OpError[V, P]) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,31 @@
+package fheap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOpError(t *testing.T) {
+	h := intMinHeap[string]()
+	if err := h.Push("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	var oe *OpError[string, int]
+	err := h.IncreasePriority("a", 2)
+	if !errors.Is(err, ErrLowerPriority) || !errors.As(err, &oe) {
+		t.Fatalf("expected an *OpError wrapping %v, got %v", ErrLowerPriority, err)
+	}
+	if oe.Op != "IncreasePriority" || oe.Value != "a" || oe.Priority != 2 {
+		t.Fatalf("expected IncreasePriority(a, 2) to fail, got %s(%s, %d)", oe.Op, oe.Value, oe.Priority)
+	}
+	err = h.Delete("b")
+	if !errors.Is(err, ErrValueNotFound) || !errors.As(err, &oe) {
+		t.Fatalf("expected an *OpError wrapping %v, got %v", ErrValueNotFound, err)
+	}
+	if oe.Op != "Delete" || oe.Value != "b" {
+		t.Fatalf("expected Delete(b) to fail, got %s(%s)", oe.Op, oe.Value)
+	}
+	if expected := "Delete b (priority 0): value missing from heap"; err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,67 @@
+package fheap
+
+import "time"
+
+// WithTTL makes values expire `ttl` after being pushed, or never if `ttl`
+// is 0, unless given an expiry time with SetExpiry. Expired values are
+// discarded once they reach the top of the heap, by Pop and Peek and their
+// variants, and reported to `onExpire` if it isn't nil. Until then, they
+// count towards the heap's size.
+func WithTTL[V comparable, P any](ttl time.Duration, onExpire func(value V, priority P)) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.expiry = &expiry[V, P]{ttl: ttl, onExpire: onExpire, now: time.Now}
+	}
+}
+
+// expiry is a heap's expiration configuration.
+type expiry[V, P any] struct {
+	ttl      time.Duration
+	onExpire func(value V, priority P)
+	now      func() time.Time
+}
+
+// SetExpiry sets the time at which a value expires, or makes it never
+// expire if `at` is the zero time. It requires the heap to have been
+// created with WithTTL.
+func (fh *Heap[V, P]) SetExpiry(value V, at time.Time) error {
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.expiry == nil {
+		return ErrUnsupported
+	}
+	if fh.unindexed {
+		return errUnindexed
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return &OpError[V, P]{Op: "SetExpiry", Value: value, Err: ErrValueNotFound}
+	}
+	x.extra().expiresAt = at
+	return nil
+}
+
+// expire discards the expired values at the top of the heap.
+func (fh *Heap[V, P]) expire() (err error) {
+	if fh == nil || fh.expiry == nil || fh.corrupted != nil {
+		return nil
+	}
+	if debug {
+		defer fh.assertInvariants()
+	}
+	defer fh.recoverPanic(&err)
+	now := fh.expiry.now()
+	for top := fh.prioritaire; top != nil; top = fh.prioritaire {
+		if expiresAt := top.expiresAt(); expiresAt.IsZero() || now.Before(expiresAt) {
+			return nil
+		}
+		fh.diagnostics.record("Expire", top.value, top.priority)
+		if err := fh.extract([]*fnode[V, P]{top}); err != nil {
+			return err
+		}
+		if fh.expiry.onExpire != nil {
+			fh.callback(func() { fh.expiry.onExpire(top.value, top.priority) })
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package fheap
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFHeapTTL(t *testing.T) {
+	var expired []string
+	h := New(func(x, y int) bool { return x < y }, math.MinInt,
+		WithTTL(time.Minute, func(value string, _ int) { expired = append(expired, value) }))
+	now := time.Unix(0, 0)
+	h.expiry.now = func() time.Time { return now }
+	for i, v := range []string{"a", "b", "c"} {
+		if err := h.Push(v, i); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Second)
+	}
+	if err := h.Push("d", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetExpiry("d", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetExpiry("b", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// lowering the priority keeps the expiry time
+	if err := h.SetPriority("b", 4); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	} else if v != "a" {
+		t.Fatalf("expected a, got %s", v)
+	}
+	if err := h.Push("a", 0); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(30 * time.Second)
+	// a was pushed again too recently to expire
+	if v, _ := h.TryPeek(); v != "a" {
+		t.Fatalf("expected a, got %s", v)
+	}
+	if err := h.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 0 {
+		t.Fatalf("expected no values to have expired, got %v", expired)
+	}
+	now = now.Add(time.Minute)
+	for _, expected := range []string{"d", "b"} {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %s, got %s", expected, v)
+		}
+	}
+	if len(expired) != 1 || expired[0] != "c" {
+		t.Fatalf("expected c to have expired, got %v", expired)
+	}
+	if _, _, err := h.Peek(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	if err := intMinHeap[string]().SetExpiry("a", now); err != ErrUnsupported {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+}
@@ -0,0 +1,150 @@
+package fheap
+
+import (
+	"fmt"
+	"math"
+)
+
+// FairQueue is a multi-tenant queue maintaining one heap per tenant. `Pop`
+// serves tenants according to weighted fair sharing, using stride
+// scheduling: each tenant has a pass which advances by the inverse of its
+// weight whenever it is served, and the non-empty tenant with the lowest
+// pass is served next. Within a tenant, values are ordered by priority.
+type FairQueue[T comparable, V comparable, P any] struct {
+	higherThan      func(x, y P) bool
+	highestPriority P
+	tenants         map[T]*tenant[V, P]
+	schedule        *Heap[T, float64]
+	vtime           float64
+}
+
+// tenant is a FairQueue tenant's heap, weight and pass.
+type tenant[V comparable, P any] struct {
+	heap   *Heap[V, P]
+	weight float64
+	pass   float64
+}
+
+// NewFairQueue creates a FairQueue without tenants. Its arguments are used
+// to create each tenant's heap, as for New.
+func NewFairQueue[T comparable, V comparable, P any](higherThan func(x, y P) bool, highestPriority P) *FairQueue[T, V, P] {
+	if higherThan == nil {
+		panic(ErrNilComparator)
+	}
+	return &FairQueue[T, V, P]{
+		higherThan:      higherThan,
+		highestPriority: highestPriority,
+		tenants:         map[T]*tenant[V, P]{},
+		schedule:        New[T](func(x, y float64) bool { return x < y }, math.Inf(-1)),
+	}
+}
+
+// Size returns the number of values queued across all tenants.
+func (fq *FairQueue[T, V, P]) Size() (int, error) {
+	if fq == nil {
+		return 0, ErrNilHeap
+	}
+	size := 0
+	for _, tn := range fq.tenants {
+		size += len(tn.heap.values)
+	}
+	return size, nil
+}
+
+// AddTenant adds a tenant with the given positive weight.
+func (fq *FairQueue[T, V, P]) AddTenant(id T, weight float64) error {
+	if fq == nil {
+		return ErrNilHeap
+	}
+	if _, ok := fq.tenants[id]; ok {
+		return fmt.Errorf("duplicate tenant=%v", id)
+	}
+	if !(weight > 0) {
+		return fmt.Errorf("tenant %v weight %v isn't positive", id, weight)
+	}
+	fq.tenants[id] = &tenant[V, P]{
+		heap:   New[V](fq.higherThan, fq.highestPriority),
+		weight: weight,
+		pass:   fq.vtime,
+	}
+	return nil
+}
+
+// RemoveTenant removes a tenant, discarding its queued values.
+func (fq *FairQueue[T, V, P]) RemoveTenant(id T) error {
+	if fq == nil {
+		return ErrNilHeap
+	}
+	tn, ok := fq.tenants[id]
+	if !ok {
+		return fmt.Errorf("tenant %v missing from queue", id)
+	}
+	if len(tn.heap.values) > 0 {
+		if err := fq.schedule.Delete(id); err != nil {
+			return err
+		}
+	}
+	delete(fq.tenants, id)
+	return nil
+}
+
+// Reweight changes a tenant's weight to the given positive weight, taking
+// effect the next time the tenant is served.
+func (fq *FairQueue[T, V, P]) Reweight(id T, weight float64) error {
+	if fq == nil {
+		return ErrNilHeap
+	}
+	tn, ok := fq.tenants[id]
+	if !ok {
+		return fmt.Errorf("tenant %v missing from queue", id)
+	}
+	if !(weight > 0) {
+		return fmt.Errorf("tenant %v weight %v isn't positive", id, weight)
+	}
+	tn.weight = weight
+	return nil
+}
+
+// Push inserts a value with the supplied priority into a tenant's heap.
+func (fq *FairQueue[T, V, P]) Push(id T, value V, priority P) error {
+	if fq == nil {
+		return ErrNilHeap
+	}
+	tn, ok := fq.tenants[id]
+	if !ok {
+		return fmt.Errorf("tenant %v missing from queue", id)
+	}
+	if err := tn.heap.Push(value, priority); err != nil {
+		return err
+	}
+	if len(tn.heap.values) == 1 {
+		// idle tenants don't accrue credit
+		tn.pass = math.Max(tn.pass, fq.vtime)
+		return fq.schedule.Push(id, tn.pass)
+	}
+	return nil
+}
+
+// Pop removes and returns the highest-priority value of the tenant due to
+// be served next, along with that tenant.
+func (fq *FairQueue[T, V, P]) Pop() (id T, value V, err error) {
+	if fq == nil {
+		return id, value, ErrNilHeap
+	}
+	id, ok := fq.schedule.TryPeek()
+	if !ok {
+		return id, value, ErrEmptyHeap
+	}
+	tn := fq.tenants[id]
+	if value, err = tn.heap.Pop(); err != nil {
+		return
+	}
+	fq.vtime = tn.pass
+	tn.pass += 1 / tn.weight
+	if len(tn.heap.values) == 0 {
+		err = fq.schedule.Delete(id)
+	} else {
+		err = fq.schedule.setPriority(id, tn.pass)
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package fheap
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFairQueue(t *testing.T) {
+	q := NewFairQueue[string, int, int](func(x, y int) bool { return x < y }, math.MinInt)
+	if _, _, err := q.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	if err := q.AddTenant("a", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.AddTenant("b", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.AddTenant("c", 0); err == nil {
+		t.Fatal("expected non-positive weight error")
+	}
+	if err := q.Push("c", 1, 1); err == nil {
+		t.Fatal("expected missing tenant error")
+	}
+	for i := 0; i < 6; i++ {
+		if err := q.Push("a", i, -i); err != nil {
+			t.Fatal(err)
+		}
+		if err := q.Push("b", i, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	served := map[string][]int{}
+	for i := 0; i < 9; i++ {
+		id, v, err := q.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		served[id] = append(served[id], v)
+	}
+	if len(served["a"]) != 6 || len(served["b"]) != 3 {
+		t.Fatalf("expected 6 values from a and 3 from b, got %v", served)
+	}
+	for i, v := range served["a"] {
+		if v != 5-i {
+			t.Fatalf("expected a's values in priority order, got %v", served["a"])
+		}
+	}
+	if err := q.RemoveTenant("b"); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := q.Size(); err != nil {
+		t.Fatal(err)
+	} else if s != 0 {
+		t.Fatalf("expected size=0, got %d", s)
+	}
+	if _, _, err := q.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+}
@@ -0,0 +1,78 @@
+package fheap
+
+import "context"
+
+// FanIn merges several input channels into a single output channel. Each
+// input's values have that input's priority, and are buffered in a
+// Fibonacci heap so that whenever the output is ready to receive, the
+// highest-priority buffered value is sent. Values of equal priority are
+// sent in arrival order. The output is closed once every input is closed
+// and drained, or once the context is done.
+func FanIn[V any, P comparable](ctx context.Context, higherThan func(x, y P) bool, inputs map[P]<-chan V) <-chan V {
+	type arrival struct {
+		priority P
+		value    V
+		closed   bool
+	}
+	type rank struct {
+		priority P
+		seq      uint64
+	}
+	merged := make(chan arrival)
+	for priority, input := range inputs {
+		go func(priority P, input <-chan V) {
+			for value := range input {
+				select {
+				case merged <- arrival{priority: priority, value: value}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case merged <- arrival{priority: priority, closed: true}:
+			case <-ctx.Done():
+			}
+		}(priority, input)
+	}
+	output := make(chan V)
+	go func() {
+		defer close(output)
+		h := NewUnreserved[uint64](func(x, y rank) bool {
+			return higherThan(x.priority, y.priority) ||
+				!higherThan(y.priority, x.priority) && x.seq < y.seq
+		})
+		buffered := map[uint64]V{}
+		var seq uint64
+		for open := len(inputs); ; {
+			var out chan<- V
+			var next V
+			id, ok := h.TryPeek()
+			if ok {
+				out = output
+				next = buffered[id]
+			} else if open == 0 {
+				return
+			}
+			select {
+			case a := <-merged:
+				if a.closed {
+					open--
+					continue
+				}
+				buffered[seq] = a.value
+				if err := h.Push(seq, rank{a.priority, seq}); err != nil {
+					return
+				}
+				seq++
+			case out <- next:
+				delete(buffered, id)
+				if _, err := h.Pop(); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return output
+}
@@ -0,0 +1,43 @@
+package fheap
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFanIn(t *testing.T) {
+	inputs := map[int]<-chan string{}
+	for p := 0; p < 3; p++ {
+		ch := make(chan string, 3)
+		for i := 0; i < 3; i++ {
+			ch <- fmt.Sprintf("p%d-%d", p, i)
+		}
+		close(ch)
+		inputs[p] = ch
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	output := FanIn(ctx, func(x, y int) bool { return x < y }, inputs)
+	// give the inputs time to be buffered
+	time.Sleep(20 * time.Millisecond)
+	var actual []string
+	for v := range output {
+		actual = append(actual, v)
+	}
+	expected := "[p0-0 p0-1 p0-2 p1-0 p1-1 p1-2 p2-0 p2-1 p2-2]"
+	if fmt.Sprint(actual) != expected {
+		t.Fatalf("expected %s, got %v", expected, actual)
+	}
+}
+
+func TestFanIn_Cancel(t *testing.T) {
+	input := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	output := FanIn(ctx, func(x, y int) bool { return x < y }, map[int]<-chan int{0: input})
+	input <- 1
+	cancel()
+	for range output {
+	}
+}
@@ -3,64 +3,254 @@ package fheap
 import (
 	"errors"
 	"fmt"
-	"math"
+	"maps"
+	"sync"
+	"time"
 )
 
-// fheap is a Fibonacci heap, consisting of a:
+// Heap is a Fibonacci heap, consisting of a:
 //   - pointer to the highest-priority element
 //   - map of values to fnodes
 //   - priority comparison function
-//   - the highest priority an element can have
+//   - the highest priority an element can have, if reserved
+//   - policy for pushing duplicate values
+//   - count of bereaved nodes
+//   - optional diagnostics state
+//   - optional size watermarks
+//   - optional value interner
+//   - optional serialisation codec
+//   - optional value and priority copiers
+//   - error flagging the heap as corrupted, if it is
+//   - optional ordering relaxation
+//   - whether to record push timestamps
+//   - optional change tracker
+//   - optional memory-pressure eviction policy
+//   - whether to break priority ties first in, first out
+//   - insertion sequence number of the last pushed node
+//   - scratch table reused by consolidations, if any
+//   - optional priority equality function
+//   - optional priority validator
+//   - optional size bound
+//   - optional expiration policy
+//   - largest number of values the value index has held
+//   - whether snapshots are ordered deterministically
+//   - whether the reserved priority passed its probe
+//   - optional pool of recycled nodes
+//   - whether values are left unindexed
+//   - number of values, if they may be repeated
+//   - number of links Push may make eagerly
+//   - upper bound on the nodes' degrees
+//   - unchecked comparison function and sampling period, if comparisons
+//     are checked
+//   - whether a user callback panicked during the current operation
 //
 // The map is used by `IncreasePriority` to find a value's corresponding node.
 // To this end, this implementation requires heap values to be comparable
-// and doesn't allow duplicate values.
+// and doesn't allow duplicate values, unless created with
+// WithAllowDuplicates, in which case the map holds one of a value's nodes,
+// the others being chained by their `twin` pointers. Heaps created with
+// WithoutIndex have no map. Heaps allowing duplicates keep their size in
+// `count`.
 // `higherThan` determines if the first priority is higher than the second.
-// `Delete` requires `higherThan` to be a connected relation on the priority
-// set, i.e. for priorities x, y, if x != y then either x is higher than y
-// or y is higher than x (https://en.wikipedia.org/wiki/Connected_relation).
-// `highestPriority` is the highest possible priority a value can have. It will
-// be reserved for internal use by `Delete`.
-type fheap[V comparable, P any] struct {
-	prioritaire     *fnode[V, P]
-	values          map[V]*fnode[V, P]
-	higherThan      func(x, y P) bool
-	highestPriority P
+// Recognising the reserved priority requires `higherThan` to be a connected
+// relation on the priority set, i.e. for priorities x, y, if x != y then
+// either x is higher than y or y is higher than x
+// (https://en.wikipedia.org/wiki/Connected_relation), unless priorities are
+// tested for equality by a function given with WithPriorityEqual.
+// `highestPriority` is the highest possible priority a value can have, and
+// is reserved: pushes and priority changes using it are rejected, which
+// keeps it a strict upper bound on the heap's priorities and lets `probe`
+// catch comparators ranking it otherwise. `reserved` is false for heaps
+// created by `NewUnreserved`, which reserve no priority.
+// `duplicates` determines what `Push` does with a value already in the heap.
+// `diagnostics` is non-nil if structural assertions are enabled after `Pop`.
+//
+// The zero value is an empty heap which pops the lowest priority first, for
+// priority types whose underlying type is ordered. Like heaps created by
+// `NewUnreserved`, it reserves no priority.
+type Heap[V comparable, P any] struct {
+	prioritaire      *fnode[V, P]
+	values           map[V]*fnode[V, P]
+	higherThan       func(x, y P) bool
+	highestPriority  P
+	reserved         bool
+	duplicates       DuplicatePolicy
+	marked           int
+	diagnostics      *diagnostics[V, P]
+	watermarks       *watermarks
+	intern           func(value V) V
+	codec            *Codec[V, P]
+	copyValue        func(value V) V
+	copyPriority     func(priority P) P
+	corrupted        error
+	relaxation       *relaxation[P]
+	timestamps       bool
+	changes          *changeTracker[V]
+	memory           *memoryPolicy[V, P]
+	fifo             bool
+	seq              uint64
+	scratch          []*fnode[V, P]
+	equal            func(a, b P) bool
+	validate         func(priority P) error
+	capacity         *capacity[V, P]
+	expiry           *expiry[V, P]
+	peak             int
+	deterministic    bool
+	probed           bool
+	pool             *sync.Pool
+	unindexed        bool
+	count            int
+	eagerLinks       int
+	maxDegree        int
+	unchecked        func(x, y P) bool
+	checkEvery       int
+	callbackPanicked bool
+}
+
+// Item is a value and its priority.
+type Item[V, P any] struct {
+	Value    V
+	Priority P
 }
 
 var ErrNilHeap = errors.New("nil heap")
 var ErrEmptyHeap = errors.New("empty heap")
-var ErrReservedPriority = errors.New("highest priority is reserved for internal use")
+var ErrReservedPriority = errors.New("highest priority is reserved")
+var ErrNilComparator = errors.New("nil priority comparison function")
+var ErrUnsupported = errors.New("unsupported operation")
+var ErrDuplicateValue = errors.New("duplicate value")
+var ErrValueNotFound = errors.New("value missing from heap")
+var ErrLowerPriority = errors.New("priority lower than current")
+var ErrMisconfigured = errors.New("misconfigured heap")
+var ErrCorrupted = errors.New("corrupted heap")
+var ErrSelfMeld = errors.New("heap melded into itself")
+var ErrDuplicateHeap = errors.New("heap melded more than once")
 
 // New creates an empty Fibonacci heap.
-func New[V comparable, P any](higherThan func(x, y P) bool, highestPriority P) *fheap[V, P] {
-	return &fheap[V, P]{
+// New panics with ErrNilComparator if `higherThan` is nil.
+func New[V comparable, P any](higherThan func(x, y P) bool, highestPriority P, opts ...Option[V, P]) *Heap[V, P] {
+	if higherThan == nil {
+		panic(ErrNilComparator)
+	}
+	fh := &Heap[V, P]{
 		values:          map[V]*fnode[V, P]{},
-		higherThan:      higherThan,
-		highestPriority: highestPriority}
+		higherThan:      guardComparator(higherThan),
+		highestPriority: highestPriority,
+		reserved:        true}
+	for _, opt := range opts {
+		opt(fh)
+	}
+	return fh
+}
+
+// NewUnreserved creates an empty Fibonacci heap which reserves no
+// priority, leaving the entire priority space usable. Such heaps support
+// every operation, including `Delete`.
+// NewUnreserved panics with ErrNilComparator if `higherThan` is nil.
+func NewUnreserved[V comparable, P any](higherThan func(x, y P) bool, opts ...Option[V, P]) *Heap[V, P] {
+	var zero P
+	fh := New(higherThan, zero, opts...)
+	fh.reserved = false
+	return fh
+}
+
+// NewWithoutDelete creates an empty Fibonacci heap which reserves no
+// priority. Despite its name, the heap supports `Delete`.
+//
+// Deprecated: Use NewUnreserved, which it's equivalent to.
+func NewWithoutDelete[V comparable, P any](higherThan func(x, y P) bool, opts ...Option[V, P]) *Heap[V, P] {
+	return NewUnreserved(higherThan, opts...)
+}
+
+// NewFromItems creates a Fibonacci heap holding the given items, which are
+// inserted into the root list in a single pass, as by PushAll.
+// NewFromItems panics with ErrNilComparator if `higherThan` is nil.
+func NewFromItems[V comparable, P any](higherThan func(x, y P) bool, highestPriority P, items []Item[V, P], opts ...Option[V, P]) (*Heap[V, P], error) {
+	fh := New(higherThan, highestPriority, opts...)
+	if err := fh.PushAll(items); err != nil {
+		return nil, err
+	}
+	return fh, nil
 }
 
 // Size returns the number of elements in the heap.
-func (fh *fheap[V, P]) Size() (int, error) {
+func (fh *Heap[V, P]) Size() (int, error) {
 	if fh == nil {
 		return 0, ErrNilHeap
 	}
-	return len(fh.values), nil
+	return fh.len(), nil
+}
+
+// Len returns the number of elements in the heap, or 0 for a nil heap.
+func (fh *Heap[V, P]) Len() int {
+	if fh == nil {
+		return 0
+	}
+	return fh.len()
+}
+
+// Contains reports whether a value is in the heap. A nil heap contains no
+// values.
+func (fh *Heap[V, P]) Contains(value V) bool {
+	if fh == nil {
+		return false
+	}
+	_, ok := fh.values[value]
+	return ok
 }
 
 // Push inserts a given value with the supplied priority into the heap.
-func (fh *fheap[V, P]) Push(value V, priority P) error {
+// Values already in the heap are handled according to the heap's
+// DuplicatePolicy.
+func (fh *Heap[V, P]) Push(value V, priority P) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
 	if fh == nil {
 		return ErrNilHeap
 	}
-	if fh.prioritiesEqual(priority, fh.highestPriority) {
-		return ErrReservedPriority
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if err := fh.lazyInit(); err != nil {
+		return err
 	}
-	if _, ok := fh.values[value]; ok {
-		return fmt.Errorf("duplicate value=%v", value)
+	fh.diagnostics.record("Push", value, priority)
+	if err := fh.relieveMemoryPressure(); err != nil {
+		return err
+	}
+	if err := fh.checkPriority(priority); err != nil {
+		return err
+	}
+	if fh.intern != nil {
+		fh.callback(func() { value = fh.intern(value) })
+	}
+	if fh.collides(value) {
+		node := fh.values[value]
+		switch fh.duplicates {
+		case DuplicatesReplace:
+			return fh.setPriority(value, priority)
+		case DuplicatesKeepBest:
+			if fh.higherThan(priority, node.priority) {
+				return fh.increasePriority(value, priority)
+			}
+			return nil
+		default:
+			return &OpError[V, P]{"Push", value, priority, ErrDuplicateValue}
+		}
 	}
-	node := newFnode(value, priority)
-	fh.values[value] = node
+	if err := fh.admit(1); err != nil {
+		return err
+	}
+	node := fh.newNode(value, priority)
+	if fh.timestamps {
+		node.extra().pushedAt = time.Now()
+	}
+	fh.index(value, node)
+	fh.changes.record(value, false)
+	fh.observeSize()
+	fh.extendBound(priority)
 	if fh.prioritaire == nil {
 		fh.prioritaire = node
 		return nil
@@ -68,109 +258,875 @@ func (fh *fheap[V, P]) Push(value V, priority P) error {
 	if err := fh.prioritaire.insertLeft(node); err != nil {
 		return err
 	}
-	if fh.higherThan(priority, fh.prioritaire.priority) {
+	if fh.nodeHigherThan(node, fh.prioritaire) {
 		fh.prioritaire = node
 	}
-	return nil
+	if err := fh.linkEagerly(node); err != nil {
+		return err
+	}
+	return fh.trim()
+}
+
+// PushAll inserts the given items into the heap. The new nodes are spliced
+// into the root list at once.
+// Values already in the heap are handled according to the heap's
+// DuplicatePolicy, however values repeated within the items are an error.
+// No item is inserted if an error is detected beforehand, in which case
+// every repeated value and reserved priority is reported.
+func (fh *Heap[V, P]) PushAll(items []Item[V, P]) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if err := fh.lazyInit(); err != nil {
+		return err
+	}
+	values := make([]V, len(items))
+	seen := make(map[V]bool, len(items))
+	var errs []error
+	incoming := 0
+	for i, item := range items {
+		values[i] = item.Value
+		if fh.intern != nil {
+			fh.callback(func() { values[i] = fh.intern(item.Value) })
+		}
+		if err := fh.checkPriority(item.Priority); err != nil {
+			errs = append(errs, &OpError[V, P]{"PushAll", values[i], item.Priority, err})
+		}
+		present := fh.collides(values[i])
+		if seen[values[i]] && !fh.allowsDuplicates() || present && fh.duplicates == DuplicatesError {
+			errs = append(errs, &OpError[V, P]{"PushAll", values[i], item.Priority, ErrDuplicateValue})
+		}
+		if !present {
+			incoming++
+		}
+		seen[values[i]] = true
+	}
+	if err := fh.admit(incoming); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if len(items) > fh.len() {
+		if err := fh.Reserve(len(items)); err != nil {
+			return err
+		}
+	}
+	var ring, best *fnode[V, P]
+	var present []int
+	var now time.Time
+	if fh.timestamps {
+		now = time.Now()
+	}
+	for i, item := range items {
+		if fh.collides(values[i]) {
+			present = append(present, i)
+			continue
+		}
+		fh.diagnostics.record("PushAll", values[i], item.Priority)
+		node := fh.newNode(values[i], item.Priority)
+		if fh.timestamps {
+			node.extra().pushedAt = now
+		}
+		fh.index(values[i], node)
+		fh.changes.record(values[i], false)
+		if ring == nil {
+			ring, best = node, node
+			continue
+		}
+		if err := ring.insertLeft(node); err != nil {
+			return err
+		}
+		if fh.nodeHigherThan(node, best) {
+			best = node
+		}
+	}
+	if ring != nil {
+		fh.observeSize()
+		fh.extendBound(best.priority)
+		if fh.prioritaire == nil {
+			fh.prioritaire = best
+		} else if err := fh.prioritaire.splice(ring); err != nil {
+			return err
+		} else if fh.nodeHigherThan(best, fh.prioritaire) {
+			fh.prioritaire = best
+		}
+	}
+	for _, i := range present {
+		if err := fh.Push(values[i], items[i].Priority); err != nil {
+			return err
+		}
+	}
+	return fh.trim()
+}
+
+// PushSet inserts the given values into the heap, all with the supplied
+// priority. The new nodes are spliced into the root list at once.
+// Values already in the heap are handled according to the heap's
+// DuplicatePolicy, however values repeated within the set are an error.
+// No value is inserted if an error is detected beforehand.
+func (fh *Heap[V, P]) PushSet(priority P, values ...V) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if err := fh.lazyInit(); err != nil {
+		return err
+	}
+	if err := fh.checkPriority(priority); err != nil {
+		return err
+	}
+	if fh.intern != nil {
+		interned := make([]V, len(values))
+		for i, value := range values {
+			fh.callback(func() { interned[i] = fh.intern(value) })
+		}
+		values = interned
+	}
+	set := make(map[V]bool, len(values))
+	incoming := 0
+	for _, value := range values {
+		present := fh.collides(value)
+		if set[value] && !fh.allowsDuplicates() || present && fh.duplicates == DuplicatesError {
+			return &OpError[V, P]{"PushSet", value, priority, ErrDuplicateValue}
+		}
+		if !present {
+			incoming++
+		}
+		set[value] = true
+	}
+	if err := fh.admit(incoming); err != nil {
+		return err
+	}
+	var ring *fnode[V, P]
+	var now time.Time
+	if fh.timestamps {
+		now = time.Now()
+	}
+	for _, value := range values {
+		if fh.collides(value) {
+			if err := fh.Push(value, priority); err != nil {
+				return err
+			}
+			continue
+		}
+		fh.diagnostics.record("PushSet", value, priority)
+		node := fh.newNode(value, priority)
+		if fh.timestamps {
+			node.extra().pushedAt = now
+		}
+		fh.index(value, node)
+		fh.changes.record(value, false)
+		if ring == nil {
+			ring = node
+		} else if err := ring.insertLeft(node); err != nil {
+			return err
+		}
+	}
+	if ring == nil {
+		return nil
+	}
+	fh.observeSize()
+	fh.extendBound(priority)
+	if fh.prioritaire == nil {
+		fh.prioritaire = ring
+		return fh.trim()
+	}
+	if err := fh.prioritaire.splice(ring); err != nil {
+		return err
+	}
+	if fh.nodeHigherThan(ring, fh.prioritaire) {
+		fh.prioritaire = ring
+	}
+	return fh.trim()
 }
 
 // Pop removes and returns the highest-priority element from the heap
-// after consolidating the heap.
-func (fh *fheap[V, P]) Pop() (value V, err error) {
+// after consolidating the heap. Heaps with relaxed ordering may return an
+// element within their epsilon of the highest priority instead.
+// Since consolidations reuse the heap's scratch table, Pop doesn't allocate
+// once the table has grown to fit the heap, unless diagnostics are enabled.
+func (fh *Heap[V, P]) Pop() (value V, err error) {
+	value, _, err = fh.PopPair()
+	return value, err
+}
+
+// PopPair is like Pop, but also returns the popped value's priority.
+func (fh *Heap[V, P]) PopPair() (value V, priority P, err error) {
+	if err := fh.expire(); err != nil {
+		return value, priority, err
+	}
+	return fh.pop()
+}
+
+// pop removes and returns the highest-priority element from the heap,
+// regardless of whether it has expired.
+func (fh *Heap[V, P]) pop() (value V, priority P, err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh != nil && fh.diagnostics != nil {
+		defer fh.diagnose()
+	}
 	defer func() {
 		if err == nil {
-			delete(fh.values, value)
+			fh.changes.record(value, true)
+			fh.observeSize()
 		}
 	}()
 	if fh == nil {
-		return value, ErrNilHeap
+		return value, priority, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return value, priority, fh.corrupted
 	}
+	defer fh.recoverPanic(&err)
 	if fh.prioritaire == nil {
-		return value, ErrEmptyHeap
+		return value, priority, ErrEmptyHeap
 	}
-	value = fh.prioritaire.Value
+	value, priority = fh.prioritaire.value, fh.prioritaire.priority
+	fh.diagnostics.record("Pop", value, fh.prioritaire.priority)
+	err = fh.removeTop(nil)
+	return
+}
+
+// removeTop removes prioritaire from the heap, its children joining the
+// root list along with `successor`, if it isn't nil, after which the heap
+// is consolidated once. The removed node's value is left indexed.
+func (fh *Heap[V, P]) removeTop(successor *fnode[V, P]) (err error) {
+	top := fh.prioritaire
 	// foster out prioritaire's children
 	var child *fnode[V, P]
-	for {
-		child, err = fh.prioritaire.popChild()
+	for n := 0; ; n++ {
+		if n > fh.len() {
+			return fh.corrupt("node %v has more children than the heap has values", top.value)
+		}
+		child, err = top.popChild()
 		if err != nil {
 			if err == errBarrenFnode {
 				err = nil
 				break
 			}
-			return
+			return err
 		}
 		child.parent = nil
 		child.left = child
 		child.right = child
-		child.bereaved = false
-		if err = fh.prioritaire.insertLeft(child); err != nil {
-			return
+		fh.unmark(child)
+		if err = top.insertLeft(child); err != nil {
+			return err
+		}
+	}
+	if successor != nil {
+		if err = top.insertLeft(successor); err != nil {
+			return err
 		}
 	}
-	// remove prioritaire from the heap's root list
-	if fh.prioritaire.left == fh.prioritaire.right && fh.prioritaire.left == fh.prioritaire {
+	// remove prioritaire from the heap's root list, unlinking it from its
+	// siblings so that its handles are known to be stale
+	if top.left == top.right && top.left == top {
 		fh.prioritaire = nil
+		fh.maxDegree = 0
 	} else {
-		fh.prioritaire.left.right = fh.prioritaire.right
-		fh.prioritaire.right.left = fh.prioritaire.left
-		fh.prioritaire = fh.prioritaire.right
-		err = fh.consolidate()
+		top.left.right = top.right
+		top.right.left = top.left
+		fh.prioritaire = top.right
+		if !fh.selectRelaxed() {
+			err = fh.consolidate()
+			if err == nil && fh.relaxation != nil {
+				fh.relaxation.bound = fh.prioritaire.priority
+			}
+		}
 	}
-	return
+	top.left, top.right = nil, nil
+	fh.unindex(top)
+	fh.release(top)
+	return err
+}
+
+// PopN pops up to k of the highest-priority elements from the heap, in
+// priority order.
+func (fh *Heap[V, P]) PopN(k int) ([]Item[V, P], error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.prioritaire == nil {
+		return nil, ErrEmptyHeap
+	}
+	k = min(k, fh.len())
+	if k <= 0 {
+		return nil, nil
+	}
+	items := make([]Item[V, P], 0, k)
+	for len(items) < k {
+		value, priority, err := fh.PopPair()
+		if err != nil {
+			return items, err
+		}
+		items = append(items, Item[V, P]{value, priority})
+	}
+	return items, nil
+}
+
+// Replace pops the highest-priority value from the heap and pushes the
+// given value with the supplied priority, as a single operation: the heap
+// is left unmodified if the value can't be pushed. If the pushed value
+// would become the highest-priority value, it takes the popped value's
+// place without consolidating the heap, and otherwise joins the root list
+// before the heap is consolidated once. Values already in the heap are
+// handled according to the heap's DuplicatePolicy.
+func (fh *Heap[V, P]) Replace(value V, priority P) (popped V, err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return popped, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return popped, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.prioritaire == nil {
+		return popped, ErrEmptyHeap
+	}
+	if err := fh.checkPriority(priority); err != nil {
+		return popped, err
+	}
+	if fh.intern != nil {
+		fh.callback(func() { value = fh.intern(value) })
+	}
+	top := fh.prioritaire
+	present := fh.collides(value) && value != top.value
+	if present && fh.duplicates == DuplicatesError {
+		return popped, &OpError[V, P]{"Replace", value, priority, ErrDuplicateValue}
+	}
+	fh.diagnostics.record("Replace", value, priority)
+	popped = top.value
+	if present {
+		// the value's priority is updated according to the duplicate
+		// policy, which can't fail, once the top is popped
+		if err := fh.removeTop(nil); err != nil {
+			return popped, err
+		}
+		fh.changes.record(popped, true)
+		fh.observeSize()
+		return popped, fh.Push(value, priority)
+	}
+	node := fh.newNode(value, priority)
+	if fh.timestamps {
+		node.extra().pushedAt = time.Now()
+	}
+	if fh.outranks(priority, top) {
+		node.children, node.degree = top.children, top.degree
+		if top.children != nil {
+			for child := top.children; ; child = child.right {
+				child.parent = node
+				if child.right == top.children {
+					break
+				}
+			}
+		}
+		if top.right != top {
+			node.left, node.right = top.left, top.right
+			top.left.right, top.right.left = node, node
+		}
+		top.left, top.right, top.children = nil, nil, nil
+		fh.prioritaire = node
+		fh.unindex(top)
+	} else if err := fh.removeTop(node); err != nil {
+		return popped, err
+	}
+	fh.index(value, node)
+	fh.changes.record(popped, true)
+	fh.changes.record(value, false)
+	fh.extendBound(priority)
+	return popped, nil
+}
+
+// PushPop pushes the given value with the supplied priority, then pops
+// the highest-priority value from the heap and returns it with its
+// priority. If the pushed value would be popped, it's returned without
+// modifying the heap.
+func (fh *Heap[V, P]) PushPop(value V, priority P) (popped V, poppedPriority P, err error) {
+	if fh == nil {
+		return popped, poppedPriority, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return popped, poppedPriority, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if err := fh.checkPriority(priority); err != nil {
+		return popped, poppedPriority, err
+	}
+	if fh.prioritaire == nil || !fh.Contains(value) && fh.outranks(priority, fh.prioritaire) {
+		return value, priority, nil
+	}
+	if err := fh.Push(value, priority); err != nil {
+		return popped, poppedPriority, err
+	}
+	return fh.PopPair()
+}
+
+// outranks determines if a value pushed with the given priority would be
+// popped before the node.
+func (fh *Heap[V, P]) outranks(priority P, x *fnode[V, P]) bool {
+	return fh.higherThan(priority, x.priority) ||
+		!fh.fifo && !fh.higherThan(x.priority, priority)
+}
+
+// TryPop pops the highest-priority value from the heap, reporting whether
+// there was one to pop.
+func (fh *Heap[V, P]) TryPop() (V, bool) {
+	value, err := fh.Pop()
+	return value, err == nil
+}
+
+// TryPeek returns the highest-priority value in the heap without removing
+// it, reporting whether there was one.
+func (fh *Heap[V, P]) TryPeek() (value V, ok bool) {
+	if fh == nil || fh.expire() != nil || fh.prioritaire == nil {
+		return
+	}
+	return fh.prioritaire.value, true
+}
+
+// Peek returns the highest-priority value in the heap and its priority
+// without removing it.
+func (fh *Heap[V, P]) Peek() (value V, priority P, err error) {
+	if fh == nil {
+		return value, priority, ErrNilHeap
+	}
+	if err := fh.expire(); err != nil {
+		return value, priority, err
+	}
+	if fh.prioritaire == nil {
+		return value, priority, ErrEmptyHeap
+	}
+	return fh.prioritaire.value, fh.prioritaire.priority, nil
+}
+
+// Clear removes every element from the heap, keeping its configuration and
+// reusing its storage. A heap flagged as corrupted is usable again after
+// being cleared.
+func (fh *Heap[V, P]) Clear() {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return
+	}
+	for node := range fh.nodes() {
+		fh.changes.record(node.value, true)
+		node.left, node.right, node.twin = nil, nil, nil
+	}
+	fh.resetIndex()
+	fh.prioritaire = nil
+	fh.maxDegree = 0
+	fh.marked = 0
+	fh.corrupted = nil
+	fh.watermarks.observe(0)
+}
+
+// Reserve grows the heap's value index to hold `n` more values without
+// rehashing, as WithCapacityHint does for new heaps.
+func (fh *Heap[V, P]) Reserve(n int) error {
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if n <= 0 || fh.unindexed {
+		return nil
+	}
+	grown := make(map[V]*fnode[V, P], fh.len()+n)
+	maps.Copy(grown, fh.values)
+	fh.values = grown
+	fh.peak = max(fh.peak, fh.len()+n)
+	return nil
+}
+
+// NodeView is a read-only view of a heap entry.
+type NodeView[V, P any] struct {
+	Value    V
+	Priority P
+	Degree   int
+	PushedAt time.Time // zero unless push timestamps are recorded
+	Meta     any       // metadata attached with SetMeta, if any
+}
+
+// PeekNode returns a view of the highest-priority entry in the heap
+// without removing it.
+func (fh *Heap[V, P]) PeekNode() (view NodeView[V, P], err error) {
+	if fh == nil {
+		return view, ErrNilHeap
+	}
+	if err := fh.expire(); err != nil {
+		return view, err
+	}
+	if fh.prioritaire == nil {
+		return view, ErrEmptyHeap
+	}
+	n := fh.prioritaire
+	return NodeView[V, P]{Value: n.value, Priority: n.priority, Degree: n.degree, PushedAt: n.pushedAt(),
+		Meta: n.meta()}, nil
 }
 
 // IncreasePriority increases a value's priority in the heap, if present.
 // An error is returned if the priority is the heap's `highestPriority`.
-func (fh *fheap[V, P]) IncreasePriority(value V, priority P) error {
+func (fh *Heap[V, P]) IncreasePriority(value V, priority P) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
 	if fh == nil {
 		return ErrNilHeap
 	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return errUnindexed
+	}
 	if fh.prioritaire == nil {
 		return ErrEmptyHeap
 	}
-	if fh.prioritiesEqual(priority, fh.highestPriority) {
-		return ErrReservedPriority
+	if err := fh.checkPriority(priority); err != nil {
+		return err
 	}
+	fh.diagnostics.record("IncreasePriority", value, priority)
 	return fh.increasePriority(value, priority)
 }
 
-// Delete deletes a value from the heap, if present. Operation consists
-// of increasing its priority to the highest priority before popping the
-// highest-priority element (itself).
-func (fh *fheap[V, P]) Delete(value V) error {
+// IncreasePriorityPrev behaves like IncreasePriority, additionally returning
+// the value's priority prior to the increase.
+func (fh *Heap[V, P]) IncreasePriorityPrev(value V, priority P) (prev P, err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return prev, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return prev, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return prev, errUnindexed
+	}
+	if fh.prioritaire == nil {
+		return prev, ErrEmptyHeap
+	}
+	if err := fh.checkPriority(priority); err != nil {
+		return prev, err
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return prev, &OpError[V, P]{"IncreasePriority", value, priority, ErrValueNotFound}
+	}
+	prev = x.priority
+	fh.diagnostics.record("IncreasePriority", value, priority)
+	err = fh.increasePriority(value, priority)
+	return
+}
+
+// PushOrUpdate inserts a value with the supplied priority if it's not in
+// the heap, and otherwise increases its priority if the supplied priority
+// is higher, reporting whether it was increased.
+func (fh *Heap[V, P]) PushOrUpdate(value V, priority P) (updated bool, err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return false, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return false, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return false, errUnindexed
+	}
+	if err := fh.checkPriority(priority); err != nil {
+		return false, err
+	}
+	if fh.intern != nil {
+		fh.callback(func() { value = fh.intern(value) })
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return false, fh.Push(value, priority)
+	}
+	if !fh.higherThan(priority, x.priority) {
+		return false, nil
+	}
+	fh.diagnostics.record("PushOrUpdate", value, priority)
+	return true, fh.increaseNode(x, priority)
+}
+
+// AdjustPriority increases a value's priority in the heap, if present, to
+// the result of applying `adjust` to its current priority.
+// An error is returned if the adjusted priority is lower, or is the heap's
+// `highestPriority`.
+func (fh *Heap[V, P]) AdjustPriority(value V, adjust func(priority P) P) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return errUnindexed
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return &OpError[V, P]{Op: "AdjustPriority", Value: value, Err: ErrValueNotFound}
+	}
+	var priority P
+	fh.callback(func() { priority = adjust(x.priority) })
+	if err := fh.checkPriority(priority); err != nil {
+		return err
+	}
+	fh.diagnostics.record("AdjustPriority", value, priority)
+	return fh.increaseNode(x, priority)
+}
+
+// SetPriority sets a value's priority in the heap, if present, in place.
+// Lowering a value's priority cuts its children to the root list, and the
+// heap is consolidated if the value was the highest-priority value.
+// An error is returned if the priority is the heap's `highestPriority`.
+func (fh *Heap[V, P]) SetPriority(value V, priority P) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
 	if fh == nil {
 		return ErrNilHeap
 	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return errUnindexed
+	}
 	if fh.prioritaire == nil {
 		return ErrEmptyHeap
 	}
-	if err := fh.increasePriority(value, fh.highestPriority); err != nil {
+	if err := fh.checkPriority(priority); err != nil {
 		return err
 	}
-	_, err := fh.Pop()
-	return err
+	fh.diagnostics.record("SetPriority", value, priority)
+	return fh.setPriority(value, priority)
+}
+
+// Fix restores the heap's order after a value's priority was modified in
+// place, e.g. through a pointer. The value is cut from its parent if its
+// priority is now higher. If any of its children's priorities are now
+// higher, its children are cut from it, and it's cut from its parent. The
+// heap is consolidated if the value was the highest-priority value.
+func (fh *Heap[V, P]) Fix(value V) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return errUnindexed
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return &OpError[V, P]{Op: "Fix", Value: value, Err: ErrValueNotFound}
+	}
+	if err := fh.checkPriority(x.priority); err != nil {
+		return err
+	}
+	fh.diagnostics.record("Fix", value, x.priority)
+	fh.changes.record(value, false)
+	fh.extendBound(x.priority)
+	lowered := false
+	if x.children != nil {
+		for child := x.children; ; child = child.right {
+			if fh.nodeHigherThan(child, x) {
+				lowered = true
+				break
+			}
+			if child.right == x.children {
+				break
+			}
+		}
+	}
+	if lowered {
+		for x.children != nil {
+			if err := fh.cut(x.children, x); err != nil {
+				return err
+			}
+		}
+	}
+	// a childless node is cut from its parent to preserve its degree bound
+	if y := x.parent; y != nil && (lowered || fh.nodeHigherThan(x, y)) {
+		if err := fh.cut(x, y); err != nil {
+			return err
+		}
+		if err := fh.cascadingCut(y); err != nil {
+			return err
+		}
+	}
+	if x == fh.prioritaire {
+		return fh.consolidate()
+	}
+	if x.parent == nil && fh.nodeHigherThan(x, fh.prioritaire) {
+		fh.prioritaire = x
+	}
+	return nil
+}
+
+// UpdateValue replaces a value in the heap with another, keeping its
+// priority and position in the heap.
+func (fh *Heap[V, P]) UpdateValue(old, new V) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	if fh.unindexed {
+		return errUnindexed
+	}
+	x, ok := fh.values[old]
+	if !ok {
+		return &OpError[V, P]{Op: "UpdateValue", Value: old, Err: ErrValueNotFound}
+	}
+	if fh.intern != nil {
+		new = fh.intern(new)
+	}
+	if new == old {
+		return nil
+	}
+	if fh.collides(new) {
+		return &OpError[V, P]{"UpdateValue", new, x.priority, ErrDuplicateValue}
+	}
+	fh.diagnostics.record("UpdateValue", new, x.priority)
+	fh.unindex(x)
+	x.value = new
+	fh.index(new, x)
+	fh.changes.record(old, true)
+	fh.changes.record(new, false)
+	return nil
+}
+
+// Delete deletes a value from the heap, if present. The value's node is cut
+// from its parent and its children join the root list, so the heap is only
+// consolidated if the value was the highest-priority value.
+func (fh *Heap[V, P]) Delete(value V) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if fh.unindexed {
+		return errUnindexed
+	}
+	if fh.prioritaire == nil {
+		return ErrEmptyHeap
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return &OpError[V, P]{Op: "Delete", Value: value, Err: ErrValueNotFound}
+	}
+	fh.diagnostics.record("Delete", value, x.priority)
+	return fh.deleteNode(x)
+}
+
+// deleteNode deletes a node from the heap. The highest-priority node is
+// popped, whereas other nodes are cut from their parents, cascading to
+// their ancestors, and then unlinked from the root list once their children
+// have joined it.
+func (fh *Heap[V, P]) deleteNode(x *fnode[V, P]) error {
+	if x == fh.prioritaire {
+		_, _, err := fh.pop()
+		return err
+	}
+	if y := x.parent; y != nil {
+		if err := fh.cut(x, y); err != nil {
+			return err
+		}
+		if err := fh.cascadingCut(y); err != nil {
+			return err
+		}
+	}
+	for n := 0; x.children != nil; n++ {
+		if n > fh.len() {
+			return fh.corrupt("node %v has more children than the heap has values", x.value)
+		}
+		if err := fh.cut(x.children, x); err != nil {
+			return err
+		}
+	}
+	x.left.right = x.right
+	x.right.left = x.left
+	x.left, x.right = nil, nil
+	fh.unindex(x)
+	fh.changes.record(x.value, true)
+	fh.observeSize()
+	fh.release(x)
+	return nil
 }
 
 // consolidate reduces the number of trees in the heap.
-func (fh *fheap[V, P]) consolidate() error {
-	D := int(math.Ceil(math.Log2(float64(len(fh.values)))))
-	A := make([]*fnode[V, P], D+1)
+func (fh *Heap[V, P]) consolidate() error {
+	A := fh.degreeTable(fh.maxDegree + 1)
+	// the table is cleared for the next consolidation, and so as not to
+	// keep nodes alive after they're removed from the heap
+	defer func() { clear(A) }()
 	end := fh.prioritaire.left
-	for w := fh.prioritaire; ; {
+	// a corrupted root list might never lead back to its end
+	for w, n := fh.prioritaire, 0; ; n++ {
+		if n > fh.len() {
+			return fh.corrupt("root list is longer than the heap's %d values", fh.len())
+		}
 		next := w.right
 		x := w
 		d := x.degree
 		for A[d] != nil {
 			y := A[d]
-			if fh.higherThan(y.priority, x.priority) {
+			if fh.nodeHigherThan(y, x) {
 				x, y = y, x
 			}
 			if err := fh.link(y, x); err != nil {
 				return err
 			}
 			A[d] = nil
-			d++
+			if d++; d == len(A) {
+				// the link raised the degree bound
+				A = fh.degreeTable(d + 1)
+			}
 		}
 		A[d] = x
 		if w == end {
@@ -195,15 +1151,29 @@ func (fh *fheap[V, P]) consolidate() error {
 		if err := fh.prioritaire.insertLeft(root); err != nil {
 			return err
 		}
-		if fh.higherThan(root.priority, fh.prioritaire.priority) {
+		if fh.nodeHigherThan(root, fh.prioritaire) {
 			fh.prioritaire = root
 		}
 	}
 	return nil
 }
 
+// degreeTable returns a table of the given size for consolidate to index
+// roots by degree. The table is the heap's scratch table, which only grows,
+// so that consolidations needn't allocate. Growing the table keeps its
+// entries, and the scratch table is cleared between consolidations. It's
+// grown to fit the degree bound D(n) at once, so that the degrees reached
+// as the heap consolidates don't each grow it.
+func (fh *Heap[V, P]) degreeTable(size int) []*fnode[V, P] {
+	if n := cap(fh.scratch); n < size {
+		grown := max(size, degreeBound(fh.len())+1)
+		fh.scratch = append(fh.scratch[:n], make([]*fnode[V, P], grown-n)...)
+	}
+	return fh.scratch[:size]
+}
+
 // link removes y from the root list, and makes y a child of x.
-func (fh *fheap[V, P]) link(y, x *fnode[V, P]) error {
+func (fh *Heap[V, P]) link(y, x *fnode[V, P]) error {
 	// remove y from the root list of H
 	y.left.right = y.right
 	y.right.left = y.left
@@ -214,33 +1184,122 @@ func (fh *fheap[V, P]) link(y, x *fnode[V, P]) error {
 		return err
 	}
 	// unmark y
-	y.bereaved = false
+	fh.unmark(y)
+	fh.maxDegree = max(fh.maxDegree, x.degree)
 	return nil
 }
 
 // prioritiesEqual determines if two priorities are equal.
-func (fh *fheap[V, P]) prioritiesEqual(a, b P) bool {
+func (fh *Heap[V, P]) prioritiesEqual(a, b P) bool {
 	// R := `higherThan` is a connected binary relation, so
 	//					x != y 	=>	xRy || yRx
 	// hence
 	//			!(xRy || yRx)	=>	!(x != y)
 	//		<=>	!xRy && !yRx	=>	x = y
+	if fh.equal != nil {
+		return fh.equal(a, b)
+	}
 	return !fh.higherThan(a, b) && !fh.higherThan(b, a)
 }
 
+// nodeHigherThan determines if the first node's priority is higher than
+// the second's, breaking ties by insertion order for FIFO heaps.
+func (fh *Heap[V, P]) nodeHigherThan(x, y *fnode[V, P]) bool {
+	if fh.higherThan(x.priority, y.priority) {
+		return true
+	}
+	if !fh.fifo || x.seq >= y.seq {
+		return false
+	}
+	if fh.equal != nil {
+		return fh.equal(x.priority, y.priority)
+	}
+	return !fh.higherThan(y.priority, x.priority)
+}
+
+// newNode creates a node for a value being inserted into the heap,
+// stamping it with the next insertion sequence number, and its expiry time
+// if values expire after a TTL.
+func (fh *Heap[V, P]) newNode(value V, priority P) *fnode[V, P] {
+	node := fh.acquire(value, priority)
+	node.owner = fh
+	fh.seq++
+	node.seq = fh.seq
+	if e := fh.expiry; e != nil && e.ttl > 0 {
+		node.extra().expiresAt = e.now().Add(e.ttl)
+	}
+	return node
+}
+
+// observeSize reports the heap's size to its watermarks, and keeps track of
+// the largest size its value index has held.
+func (fh *Heap[V, P]) observeSize() {
+	fh.peak = max(fh.peak, fh.len())
+	fh.watermarks.observe(fh.len())
+}
+
+// checkPriority returns an error if a priority can't be given to a value,
+// either because it's reserved or it's deemed invalid by
+// the heap's priority validator.
+func (fh *Heap[V, P]) checkPriority(priority P) error {
+	if fh.validate != nil {
+		var err error
+		fh.callback(func() { err = fh.validate(priority) })
+		if err != nil {
+			return err
+		}
+	}
+	if fh.isReserved(priority) {
+		return ErrReservedPriority
+	}
+	return fh.probe(priority)
+}
+
+// probe checks the heap's reserved priority against the first priority
+// given to the heap, returning an error wrapping ErrMisconfigured if the
+// comparison function doesn't rank the reserved priority highest, e.g.
+// because the lowest priority was passed as `highestPriority`.
+func (fh *Heap[V, P]) probe(priority P) error {
+	if !fh.reserved || fh.probed {
+		return nil
+	}
+	if fh.higherThan(fh.highestPriority, fh.highestPriority) {
+		return fmt.Errorf("%w: highestPriority %v is higher than itself",
+			ErrMisconfigured, fh.highestPriority)
+	}
+	if !fh.higherThan(fh.highestPriority, priority) {
+		return fmt.Errorf("%w: highestPriority %v isn't higher than priority %v",
+			ErrMisconfigured, fh.highestPriority, priority)
+	}
+	fh.probed = true
+	return nil
+}
+
+// isReserved determines if a priority is the heap's reserved priority.
+func (fh *Heap[V, P]) isReserved(priority P) bool {
+	return fh.reserved && fh.prioritiesEqual(priority, fh.highestPriority)
+}
+
 // increasePriority increases the priority of a value in the heap, if present.
-// For internal use, as it allows setting the priority to `highestPriority`.
-func (fh *fheap[V, P]) increasePriority(value V, priority P) error {
+// Unlike IncreasePriority, it doesn't check the priority.
+func (fh *Heap[V, P]) increasePriority(value V, priority P) error {
 	x, ok := fh.values[value]
 	if !ok {
-		return fmt.Errorf("value %v missing from heap", value)
+		return &OpError[V, P]{"IncreasePriority", value, priority, ErrValueNotFound}
 	}
+	return fh.increaseNode(x, priority)
+}
+
+// increaseNode increases the priority of a node in the heap.
+func (fh *Heap[V, P]) increaseNode(x *fnode[V, P], priority P) error {
 	if fh.higherThan(x.priority, priority) {
-		return fmt.Errorf("old priority %v is higher than new %v", x.priority, priority)
+		return &OpError[V, P]{"IncreasePriority", x.value, priority, fmt.Errorf("%w %v", ErrLowerPriority, x.priority)}
 	}
 	x.priority = priority
+	fh.changes.record(x.value, false)
+	fh.extendBound(priority)
 	y := x.parent
-	if y != nil && fh.higherThan(x.priority, y.priority) {
+	if y != nil && fh.nodeHigherThan(x, y) {
 		if err := fh.cut(x, y); err != nil {
 			return err
 		}
@@ -248,38 +1307,94 @@ func (fh *fheap[V, P]) increasePriority(value V, priority P) error {
 			return err
 		}
 	}
-	if fh.higherThan(x.priority, fh.prioritaire.priority) {
+	if fh.nodeHigherThan(x, fh.prioritaire) {
 		fh.prioritaire = x
 	}
 	return nil
 }
 
+// setPriority sets the priority of a value in the heap, if present.
+func (fh *Heap[V, P]) setPriority(value V, priority P) error {
+	x, ok := fh.values[value]
+	if !ok {
+		return &OpError[V, P]{"SetPriority", value, priority, ErrValueNotFound}
+	}
+	if !fh.higherThan(x.priority, priority) {
+		return fh.increasePriority(value, priority)
+	}
+	return fh.lowerNode(x, priority)
+}
+
+// lowerNode lowers a node's priority in place. Its children, which may now
+// be higher, are cut to the root list, and it's then cut from its parent,
+// as a childless node, to preserve its parent's degree bound. The heap is
+// consolidated if the node was the highest-priority node.
+func (fh *Heap[V, P]) lowerNode(x *fnode[V, P], priority P) error {
+	x.priority = priority
+	fh.changes.record(x.value, false)
+	if x.children != nil {
+		for n := 0; x.children != nil; n++ {
+			if n > fh.len() {
+				return fh.corrupt("node %v has more children than the heap has values", x.value)
+			}
+			if err := fh.cut(x.children, x); err != nil {
+				return err
+			}
+		}
+		if y := x.parent; y != nil {
+			if err := fh.cut(x, y); err != nil {
+				return err
+			}
+			if err := fh.cascadingCut(y); err != nil {
+				return err
+			}
+		}
+	}
+	if x == fh.prioritaire {
+		return fh.consolidate()
+	}
+	return nil
+}
+
 // cut severs the link between x and its parent y, and turns x into a root.
-func (fh *fheap[V, P]) cut(x, y *fnode[V, P]) error {
+func (fh *Heap[V, P]) cut(x, y *fnode[V, P]) error {
 	if err := y.removeChild(x); err != nil {
 		return err
 	}
 	x.left = x
 	x.right = x
 	x.parent = nil
-	x.bereaved = false
+	fh.unmark(x)
 	return fh.prioritaire.insertLeft(x)
 }
 
-// cascadingCut handles the ancestral consequences of cutting a node.
-func (fh *fheap[V, P]) cascadingCut(y *fnode[V, P]) error {
-	z := y.parent
-	if z != nil {
+// unmark clears a node's bereavement flag.
+func (fh *Heap[V, P]) unmark(x *fnode[V, P]) {
+	if x.bereaved {
+		x.bereaved = false
+		fh.marked--
+	}
+}
+
+// cascadingCut handles the ancestral consequences of cutting a node: the
+// node's bereaved ancestors are cut in turn, up to the first ancestor that
+// wasn't bereaved, which is marked as such unless it's a root.
+// It iterates rather than recursing, since ancestor chains can be long.
+func (fh *Heap[V, P]) cascadingCut(y *fnode[V, P]) error {
+	for n := 0; y.parent != nil; n++ {
+		if n > fh.len() {
+			return fh.corrupt("node %v has more ancestors than the heap has values", y.value)
+		}
 		if !y.bereaved {
 			y.bereaved = true
-		} else {
-			if err := fh.cut(y, z); err != nil {
-				return err
-			}
-			if err := fh.cascadingCut(z); err != nil {
-				return err
-			}
+			fh.marked++
+			return nil
+		}
+		z := y.parent
+		if err := fh.cut(y, z); err != nil {
+			return err
 		}
+		y = z
 	}
 	return nil
 }
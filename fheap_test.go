@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 var HeapSize = flag.Int("heapsize", 100, "size of arbitrary heap when testing")
 
-func isOrderedHeap[V comparable, P any](h *fheap[V, P], n *fnode[V, P]) error {
+func isOrderedHeap[V comparable, P any](h *Heap[V, P], n *fnode[V, P]) error {
 	if n == nil {
 		return errNilFnode
 	}
@@ -20,7 +24,7 @@ func isOrderedHeap[V comparable, P any](h *fheap[V, P], n *fnode[V, P]) error {
 	if n.children != nil {
 		for child := n.children; ; child = child.right {
 			if h.higherThan(child.priority, n.priority) {
-				return fmt.Errorf("%s: parent (v=%v) priority %v lower than child's (v=%v) %v", prefix, n.Value, n.priority, child.Value, child.priority)
+				return fmt.Errorf("%s: parent (v=%v) priority %v lower than child's (v=%v) %v", prefix, n.value, n.priority, child.value, child.priority)
 			}
 			if child.parent != n {
 				return fmt.Errorf("%s: child's parent = %v", prefix, child.parent)
@@ -40,7 +44,7 @@ func isOrderedHeap[V comparable, P any](h *fheap[V, P], n *fnode[V, P]) error {
 	return fmt.Errorf("%s: counted %d children, but degree=%d", prefix, numChildren, n.degree)
 }
 
-func isFibonacciHeap[V comparable, P any](h *fheap[V, P]) error {
+func isFibonacciHeap[V comparable, P any](h *Heap[V, P]) error {
 	if h == nil {
 		return nil
 	}
@@ -65,11 +69,11 @@ func isFibonacciHeap[V comparable, P any](h *fheap[V, P]) error {
 	return nil
 }
 
-func intMinHeap[V comparable]() *fheap[V, int] {
+func intMinHeap[V comparable]() *Heap[V, int] {
 	return New[V, int](func(x, y int) bool { return x < y }, math.MinInt)
 }
 
-func Push[V comparable, P any](h *fheap[V, P], v V, p P, name string) error {
+func Push[V comparable, P any](h *Heap[V, P], v V, p P, name string) error {
 	if err := h.Push(v, p); err != nil {
 		return fmt.Errorf("[%s] Push(p=%v, v=%v) failed with %w", name, p, v, err)
 	}
@@ -79,7 +83,7 @@ func Push[V comparable, P any](h *fheap[V, P], v V, p P, name string) error {
 	return nil
 }
 
-func Pop[V comparable, P any](h *fheap[V, P], name string) (V, error) {
+func Pop[V comparable, P any](h *Heap[V, P], name string) (V, error) {
 	v, err := h.Pop()
 	if err != nil {
 		return v, fmt.Errorf("[%s] Pop() failed with %w", name, err)
@@ -90,7 +94,7 @@ func Pop[V comparable, P any](h *fheap[V, P], name string) (V, error) {
 	return v, nil
 }
 
-func IncreasePriority[V comparable, P any](h *fheap[V, P], v V, p P, name string) error {
+func IncreasePriority[V comparable, P any](h *Heap[V, P], v V, p P, name string) error {
 	if err := h.IncreasePriority(v, p); err != nil {
 		return fmt.Errorf("[%s] IncreasePriority(v=%v, p=%v) failed with %w", name, v, p, err)
 	}
@@ -100,7 +104,7 @@ func IncreasePriority[V comparable, P any](h *fheap[V, P], v V, p P, name string
 	return nil
 }
 
-func Delete[V comparable, P any](h *fheap[V, P], v V, name string) error {
+func Delete[V comparable, P any](h *Heap[V, P], v V, name string) error {
 	if err := h.Delete(v); err != nil {
 		return fmt.Errorf("[%s] Delete(v=%v) failed with %w", name, v, err)
 	}
@@ -110,103 +114,980 @@ func Delete[V comparable, P any](h *fheap[V, P], v V, name string) error {
 	return nil
 }
 
+func TestFHeap_NilComparator(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrNilComparator {
+			t.Fatalf("expected panic with %v, got %v", ErrNilComparator, r)
+		}
+	}()
+	New[int, int](nil, math.MinInt)
+}
+
 func TestFHeap_NilHeap(t *testing.T) {
-	var h *fheap[int, int]
+	var h *Heap[int, int]
 	e := ErrNilHeap
 	msg := fmt.Sprintf("[%s] expected %v, got %v", "%s", e, "%v")
 	if _, err := h.Size(); err != e {
 		t.Fatalf(msg, "Size", err)
 	}
-	if err := h.Push(1, 1); err != e {
-		t.Fatalf(msg, "Push", err)
+	if err := h.Push(1, 1); err != e {
+		t.Fatalf(msg, "Push", err)
+	}
+	if _, err := h.Pop(); err != e {
+		t.Fatalf(msg, "Pop", err)
+	}
+	if err := h.IncreasePriority(2, 7); err != e {
+		t.Fatalf(msg, "IncreasePriority", err)
+	}
+	if err := h.Delete(12); err != e {
+		t.Fatalf(msg, "Delete", err)
+	}
+}
+
+func TestFHeap_EmptyHeap(t *testing.T) {
+	h := intMinHeap[string]()
+	if s, err := h.Size(); err != nil {
+		t.Fatalf("[Size] failed with %v", err)
+	} else if s != 0 {
+		t.Fatalf("[Size] expected size=0, got %d", s)
+	}
+	if _, err := h.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("[Pop] expected ErrEmptyHeap, got err=%v", err)
+	}
+	value := "3-2-1 girls wanna have fun, if the man don't dance he's done"
+	if err := h.IncreasePriority(value, 12); err != ErrEmptyHeap {
+		t.Fatalf("[IncreasePriority] expected ErrEmptyHeap, got err=%v", err)
+	}
+	if err := h.Delete("wesh gros"); err != ErrEmptyHeap {
+		t.Fatalf("[Delete] expected ErrEmptyHeap, got err=%v", err)
+	}
+}
+
+func TestFHeapPush(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for i := N; i > 0; i-- {
+		if err := Push(h, i, i*2, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if size, err := h.Size(); err != nil {
+		t.Fatal(err)
+	} else if size != N {
+		t.Fatalf("expected size=1, got %d", size)
+	}
+	err := Push(h, N, 123123123, t.Name())
+	if !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	var oe *OpError[int, int]
+	if !errors.As(err, &oe) || oe.Op != "Push" || oe.Value != N || oe.Priority != 123123123 {
+		t.Fatalf("expected an *OpError for Push(%d, 123123123), got %v", N, err)
+	}
+	if !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v to wrap %v", err, ErrDuplicateValue)
+	}
+}
+
+func TestFHeapPush_DuplicatePolicy(t *testing.T) {
+	type testcase struct {
+		name     string
+		policy   DuplicatePolicy
+		first    int
+		second   int
+		expected int
+	}
+	testcases := []testcase{
+		{"replace with higher", DuplicatesReplace, 10, 5, 5},
+		{"replace with lower", DuplicatesReplace, 5, 10, 10},
+		{"keep best, higher", DuplicatesKeepBest, 10, 5, 5},
+		{"keep best, lower", DuplicatesKeepBest, 5, 10, 5},
+	}
+	for _, tc := range testcases {
+		h := New[int, int](func(x, y int) bool { return x < y }, math.MinInt,
+			WithDuplicatePolicy[int, int](tc.policy))
+		prefix := fmt.Sprintf("[%s | %s]", t.Name(), tc.name)
+		for i := 0; i < 5; i++ {
+			if err := Push(h, i, i, prefix); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := Push(h, 10, tc.first, prefix); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Pop(h, prefix); err != nil {
+			t.Fatal(err)
+		}
+		if err := Push(h, 10, tc.second, prefix); err != nil {
+			t.Fatal(err)
+		}
+		if size, err := h.Size(); err != nil {
+			t.Fatal(err)
+		} else if size != 5 {
+			t.Fatalf("%s expected size=5, got %d", prefix, size)
+		}
+		if actual := h.values[10].priority; actual != tc.expected {
+			t.Fatalf("%s expected priority=%d, got %d", prefix, tc.expected, actual)
+		}
+	}
+}
+
+func TestFHeapWithAllowDuplicates(t *testing.T) {
+	h := New(func(x, y int) bool { return x < y }, math.MinInt, WithAllowDuplicates[int, int](),
+		WithChangeTracking[int, int]())
+	N := *HeapSize
+	// every value is pushed three times, with priorities v, v+N and v+2N
+	for round := range 3 {
+		for _, v := range rand.Perm(N) {
+			if err := Push(h, v, v+round*N, t.Name()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := h.PushAll([]Item[int, int]{{N, 3 * N}, {N, 3 * N}}); err != nil {
+		t.Fatal(err)
+	}
+	if size, _ := h.Size(); size != 3*N+2 {
+		t.Fatalf("expected size=%d, got %d", 3*N+2, size)
+	}
+	// Delete removes one occurrence at a time
+	for range 2 {
+		if err := h.Delete(N); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if h.Contains(N) {
+		t.Fatalf("expected %d to be deleted", N)
+	}
+	if err := h.IncreasePriority(0, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.UpdateValue(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.DiffSince(0); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := h.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clone.Equal(h) {
+		t.Fatal("expected the clone to equal the heap")
+	}
+	if err := h.Meld(clone); err != nil {
+		t.Fatal(err)
+	}
+	if size, _ := h.Size(); size != 6*N {
+		t.Fatalf("expected size=%d, got %d", 6*N, size)
+	}
+	if v, p, err := h.PopPair(); err != nil || v != 1 || p != -1 {
+		t.Fatalf("expected (1, -1, nil), got (%d, %d, %v)", v, p, err)
+	}
+	for i := 1; i < 6*N; i++ {
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if size, _ := h.Size(); size != 0 || h.Contains(1) {
+		t.Fatalf("expected an empty heap, got size=%d", size)
+	}
+}
+
+func TestFHeapPush_Interner(t *testing.T) {
+	interned := 0
+	intern := UniqueInterner[string]()
+	h := New[string, int](func(x, y int) bool { return x < y }, math.MinInt,
+		WithInterner[string, int](func(v string) string {
+			interned++
+			return intern(v)
+		}))
+	base := "https://example.com/some/long/path/"
+	for i := 0; i < 3; i++ {
+		if err := Push(h, base+strconv.Itoa(i), i, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if interned != 3 {
+		t.Fatalf("expected 3 interned values, got %d", interned)
+	}
+	value := base + "1"
+	if unsafe.StringData(h.values[value].value) != unsafe.StringData(intern(value)) {
+		t.Fatal("expected the stored value to be interned")
+	}
+}
+
+func TestFHeapPushSet(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for i := 0; i < N; i++ {
+		if err := Push(h, i, 2*i+1, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.PushSet(2, N, N+1, N+2); err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.PushSet(0, N+3, N+3); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if err := h.PushSet(0, N+3, 1); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if _, ok := h.values[N+3]; ok {
+		t.Fatal("expected no value to be inserted")
+	}
+	if err := h.PushSet(0); err != nil {
+		t.Fatal(err)
+	}
+	tied := map[int]bool{}
+	for i := 0; i < 3; i++ {
+		v, err := Pop(h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		tied[v] = true
+	}
+	if !tied[N] || !tied[N+1] || !tied[N+2] {
+		t.Fatalf("expected values %d, %d and %d first, got %v", N, N+1, N+2, tied)
+	}
+	if size, _ := h.Size(); size != N-1 {
+		t.Fatalf("expected size=%d, got %d", N-1, size)
+	}
+}
+
+func TestFHeapPop_OneInOneOut(t *testing.T) {
+	h := intMinHeap[int]()
+	v := 34
+	if err := Push(h, v, 12, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if actual, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	} else if actual != v {
+		t.Fatalf("expected highest-priority value to be %d, got %d", v, actual)
+	}
+	if size, err := h.Size(); err != nil {
+		t.Fatal(err)
+	} else if size != 0 {
+		t.Fatalf("expected empty heap, got size=%d", size)
+	}
+}
+
+func TestFHeapPop_RandomPermutation(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	perm := rand.Perm(N)
+	for _, p := range perm {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for expected := 0; expected < N; expected++ {
+		if actual, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if actual != expected {
+			t.Fatalf("[i=%[1]d] expected value=%[1]d, got %[2]d", expected, actual)
+		}
+	}
+}
+
+func TestFHeapTryPopTryPeek(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	if _, ok := nilHeap.TryPop(); ok {
+		t.Fatal("[TryPop] expected ok=false for nil heap")
+	}
+	if _, ok := nilHeap.TryPeek(); ok {
+		t.Fatal("[TryPeek] expected ok=false for nil heap")
+	}
+	h := intMinHeap[int]()
+	if _, ok := h.TryPeek(); ok {
+		t.Fatal("[TryPeek] expected ok=false for empty heap")
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for expected := 0; expected < N; expected++ {
+		if v, ok := h.TryPeek(); !ok || v != expected {
+			t.Fatalf("[TryPeek] expected (%d, true), got (%d, %t)", expected, v, ok)
+		}
+		if v, ok := h.TryPop(); !ok || v != expected {
+			t.Fatalf("[TryPop] expected (%d, true), got (%d, %t)", expected, v, ok)
+		}
+	}
+	if _, ok := h.TryPop(); ok {
+		t.Fatal("[TryPop] expected ok=false for empty heap")
+	}
+}
+
+func TestFHeapPeek(t *testing.T) {
+	var nilHeap *Heap[int, string]
+	if _, _, err := nilHeap.Peek(); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+	h := intMinHeap[string]()
+	if _, _, err := h.Peek(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, strconv.Itoa(p), p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for expected := 0; expected < N; expected++ {
+		v, p, err := h.Peek()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != strconv.Itoa(expected) || p != expected {
+			t.Fatalf("expected (%d, %d), got (%s, %d)", expected, expected, v, p)
+		}
+		if size, _ := h.Size(); size != N-expected {
+			t.Fatalf("Peek changed heap size: expected %d, got %d", N-expected, size)
+		}
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFHeapPopPair(t *testing.T) {
+	var nilHeap *Heap[int, string]
+	if _, _, err := nilHeap.PopPair(); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+	h := intMinHeap[string]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, strconv.Itoa(p), p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for expected := 0; expected < N; expected++ {
+		v, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != strconv.Itoa(expected) || p != expected {
+			t.Fatalf("expected (%d, %d), got (%s, %d)", expected, expected, v, p)
+		}
+	}
+	if _, p, err := h.PopPair(); err != ErrEmptyHeap || p != 0 {
+		t.Fatalf("expected (0, %v), got (%d, %v)", ErrEmptyHeap, p, err)
+	}
+}
+
+func TestFHeapLen(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	if n := nilHeap.Len(); n != 0 {
+		t.Fatalf("expected 0 for nil heap, got %d", n)
+	}
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for i, p := range rand.Perm(N) {
+		if n := h.Len(); n != i {
+			t.Fatalf("expected %d, got %d", i, n)
+		}
+		if err := h.Push(p, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, _ := h.Size(); n != h.Len() {
+		t.Fatalf("Size()=%d disagrees with Len()=%d", n, h.Len())
+	}
+}
+
+func TestNewFromItems(t *testing.T) {
+	N := *HeapSize
+	items := make([]Item[int, int], N)
+	for i, p := range rand.Perm(N) {
+		items[i] = Item[int, int]{p, p}
+	}
+	h, err := NewFromItems(func(x, y int) bool { return x < y }, math.MinInt, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	for expected := 0; expected < N; expected++ {
+		v, err := Pop(h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+	if h, err := NewFromItems(func(x, y int) bool { return x < y }, math.MinInt,
+		[]Item[int, int]{{1, 1}, {2, 2}, {1, 3}}); !errors.Is(err, ErrDuplicateValue) || h != nil {
+		t.Fatalf("expected duplicate value error, got (%v, %v)", h, err)
+	}
+	if _, err := NewFromItems(func(x, y int) bool { return x < y }, math.MinInt,
+		[]Item[int, int]{{1, 1}, {2, math.MinInt}}); !errors.Is(err, ErrReservedPriority) {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+}
+
+func TestFHeapSetPriority(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// rotate the priorities: lowering all but the last
+	for v := 0; v < N; v++ {
+		if err := h.SetPriority(v, (v+1)%N); err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := N - 1
+	for i := 0; i < N; i++ {
+		v, err := Pop(h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+		expected = (expected + 1) % N
+	}
+	if err := h.SetPriority(0, 0); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	if err := Push(h, 0, 0, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetPriority(0, math.MinInt); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := h.SetPriority(1, 1); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
+	}
+	unreserved := NewUnreserved[int, int](func(x, y int) bool { return x < y })
+	if err := unreserved.Push(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := unreserved.SetPriority(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, p, err := unreserved.Peek(); err != nil || p != 1 {
+		t.Fatalf("expected priority 1, got (%d, %v)", p, err)
+	}
+}
+
+func TestFHeapSetPriority_InPlace(t *testing.T) {
+	var marks []int
+	h := New(func(x, y int) bool { return x < y }, math.MinInt,
+		WithFIFOTies[int, int](), WithChangeTracking[int, int](),
+		WithWatermarks[int, int](2, 3, func(n int) { marks = append(marks, n) },
+			func(n int) { marks = append(marks, -n) }))
+	handles := make([]*Handle[int, int], 3)
+	for v := range handles {
+		handle, err := h.PushHandle(v, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handles[v] = handle
+	}
+	version, _ := h.Version()
+	marks = nil
+	// 0 is lowered below 2, and should stay ahead of it in insertion order
+	if err := h.SetPriority(0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(marks) != 0 {
+		t.Fatalf("expected no watermark callbacks, got %v", marks)
+	}
+	if v, _ := h.Version(); v != version+1 {
+		t.Fatalf("expected one recorded change, got %d", v-version)
+	}
+	if handles[0].Stale() {
+		t.Fatal("expected the handle to stay valid")
+	}
+	if err := h.IncreasePriorityHandle(handles[0], 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetPriority(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []int{0, 1, 2} {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
+
+func TestFHeapPushOrUpdate(t *testing.T) {
+	h := intMinHeap[string]()
+	cases := []struct {
+		value    string
+		priority int
+		updated  bool
+	}{
+		{"a", 5, false},
+		{"b", 3, false},
+		{"a", 6, false},
+		{"a", 5, false},
+		{"a", 1, true},
+		{"b", 2, true},
+		{"c", 4, false},
+	}
+	for _, c := range cases {
+		updated, err := h.PushOrUpdate(c.value, c.priority)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated != c.updated {
+			t.Fatalf("PushOrUpdate(%q, %d): expected updated=%t, got %t", c.value, c.priority, c.updated, updated)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, expected := range []string{"a", "b", "c"} {
+		v, err := Pop(h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Fatalf("expected %q, got %q", expected, v)
+		}
+	}
+	if _, err := h.PushOrUpdate("a", math.MinInt); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+}
+
+func TestFHeapContains(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	if nilHeap.Contains(0) {
+		t.Fatal("expected nil heap to contain nothing")
+	}
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(2*p, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for v := 0; v < 2*N; v++ {
+		if h.Contains(v) != (v%2 == 0) {
+			t.Fatalf("Contains(%d)=%t", v, h.Contains(v))
+		}
+	}
+	v, err := h.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Contains(v) {
+		t.Fatalf("expected popped value %d to be gone", v)
+	}
+}
+
+func TestFHeapClear(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	nilHeap.Clear()
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for round := 0; round < 2; round++ {
+		for _, p := range rand.Perm(N) {
+			if err := Push(h, p, p, t.Name()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+		h.Clear()
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+		if n := h.Len(); n != 0 {
+			t.Fatalf("expected empty heap, got size=%d", n)
+		}
+		if _, err := h.Pop(); err != ErrEmptyHeap {
+			t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+		}
+	}
+}
+
+func TestFHeapPopN(t *testing.T) {
+	h := intMinHeap[int]()
+	if _, err := h.PopN(1); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if items, err := h.PopN(0); err != nil || len(items) != 0 {
+		t.Fatalf("expected no items, got (%v, %v)", items, err)
+	}
+	expected := 0
+	for _, k := range []int{1, N / 3, N} {
+		items, err := h.PopN(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != min(k, N-expected) {
+			t.Fatalf("PopN(%d): expected %d items, got %d", k, min(k, N-expected), len(items))
+		}
+		for _, item := range items {
+			if item.Value != expected || item.Priority != expected {
+				t.Fatalf("expected (%d, %d), got (%d, %d)", expected, expected, item.Value, item.Priority)
+			}
+			expected++
+		}
+	}
+	if h.Len() != 0 {
+		t.Fatalf("expected empty heap, got size=%d", h.Len())
+	}
+}
+
+func TestFHeapPushAll(t *testing.T) {
+	h := New[int, int](func(x, y int) bool { return x < y }, math.MinInt,
+		WithDuplicatePolicy[int, int](DuplicatesKeepBest))
+	N := *HeapSize
+	perm := rand.Perm(N)
+	for _, p := range perm[:N/2] {
+		if err := Push(h, p, p+N, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	items := make([]Item[int, int], N)
+	for i, p := range perm {
+		items[i] = Item[int, int]{p, p}
+	}
+	if err := h.PushAll(items); err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	for expected := 0; expected < N; expected++ {
+		v, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected || p != expected {
+			t.Fatalf("expected (%d, %d), got (%d, %d)", expected, expected, v, p)
+		}
+	}
+	err := h.PushAll([]Item[int, int]{{1, 1}, {2, math.MinInt}, {1, 2}, {3, 3}, {3, 3}})
+	if !errors.Is(err, ErrDuplicateValue) || !errors.Is(err, ErrReservedPriority) {
+		t.Fatalf("expected duplicate value and reserved priority errors, got %v", err)
+	}
+	if n := strings.Count(err.Error(), "\n") + 1; n != 3 {
+		t.Fatalf("expected 3 errors reported, got %d: %v", n, err)
+	}
+	if h.Len() != 0 {
+		t.Fatalf("expected no items to be inserted, got size=%d", h.Len())
+	}
+}
+
+func TestFHeapReplace(t *testing.T) {
+	h := intMinHeap[int]()
+	if _, err := h.Replace(0, 0); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// give the heap some structure
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	handle, err := h.PushHandle(-1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		value, priority, popped int
+		err                     error
+	}{
+		{-2, -1, -1, nil}, // outranks the popped value
+		{-3, -1, -2, nil}, // ties with the popped value
+		{N, N, -3, nil},   // joins the root list
+		{1, 0, 1, nil},    // replaces itself
+		{2, N, 0, ErrDuplicateValue},
+	}
+	for _, c := range cases {
+		popped, err := h.Replace(c.value, c.priority)
+		if !errors.Is(err, c.err) {
+			t.Fatalf("Replace(%d, %d): expected %v, got %v", c.value, c.priority, c.err, err)
+		}
+		if popped != c.popped {
+			t.Fatalf("Replace(%d, %d): expected %d, got %d", c.value, c.priority, c.popped, popped)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !handle.Stale() {
+		t.Fatal("expected handle of replaced value to be stale")
+	}
+	// the failed replacement left the heap untouched
+	for expected := 1; expected <= N; expected++ {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
+
+func TestFHeapReplace_Duplicates(t *testing.T) {
+	h := intMinHeap[int]()
+	for v := 1; v <= 2; v++ {
+		if err := h.Push(v, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := h.Replace(2, 5); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if h.Len() != 2 || !h.Contains(1) {
+		t.Fatalf("expected the heap to be untouched, got size=%d", h.Len())
+	}
+	h.duplicates = DuplicatesReplace
+	if popped, err := h.Replace(2, 5); err != nil || popped != 1 {
+		t.Fatalf("expected (1, nil), got (%d, %v)", popped, err)
+	}
+	if _, p, err := h.Peek(); err != nil || p != 5 || h.Len() != 1 {
+		t.Fatalf("expected 2 to be left with priority 5, got (%d, %v) and size=%d", p, err, h.Len())
+	}
+}
+
+func TestFHeapPushPop(t *testing.T) {
+	h := intMinHeap[int]()
+	if v, p, err := h.PushPop(1, 1); err != nil || v != 1 || p != 1 || h.Len() != 0 {
+		t.Fatalf("expected (1, 1, nil) from empty heap, got (%d, %d, %v)", v, p, err)
+	}
+	// streaming top-k: keep the k largest values in a min-heap
+	N, k := *HeapSize, 10
+	perm := rand.Perm(N)
+	for _, v := range perm[:k] {
+		if err := Push(h, v, v, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range perm[k:] {
+		if _, _, err := h.PushPop(v, v); err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for expected := N - k; expected < N; expected++ {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
 	}
-	if _, err := h.Pop(); err != e {
-		t.Fatalf(msg, "Pop", err)
+	if err := Push(h, 5, 5, t.Name()); err != nil {
+		t.Fatal(err)
 	}
-	if err := h.IncreasePriority(2, 7); err != e {
-		t.Fatalf(msg, "IncreasePriority", err)
+	if _, _, err := h.PushPop(5, 0); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
 	}
-	if err := h.Delete(12); err != e {
-		t.Fatalf(msg, "Delete", err)
+	if _, _, err := h.PushPop(6, math.MinInt); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
 	}
 }
 
-func TestFHeap_EmptyHeap(t *testing.T) {
+func TestFHeapUpdateValue(t *testing.T) {
 	h := intMinHeap[string]()
-	if s, err := h.Size(); err != nil {
-		t.Fatalf("[Size] failed with %v", err)
-	} else if s != 0 {
-		t.Fatalf("[Size] expected size=0, got %d", s)
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, strconv.Itoa(p), p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
 	}
-	if _, err := h.Pop(); err != ErrEmptyHeap {
-		t.Fatalf("[Pop] expected ErrEmptyHeap, got err=%v", err)
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
 	}
-	value := "3-2-1 girls wanna have fun, if the man don't dance he's done"
-	if err := h.IncreasePriority(value, 12); err != ErrEmptyHeap {
-		t.Fatalf("[IncreasePriority] expected ErrEmptyHeap, got err=%v", err)
+	for p := 1; p < N; p++ {
+		if err := h.UpdateValue(strconv.Itoa(p), "job"+strconv.Itoa(p)); err != nil {
+			t.Fatal(err)
+		}
 	}
-	if err := h.Delete("wesh gros"); err != ErrEmptyHeap {
-		t.Fatalf("[Delete] expected ErrEmptyHeap, got err=%v", err)
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.UpdateValue("1", "x"); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
+	}
+	if err := h.UpdateValue("job1", "job2"); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if err := h.UpdateValue("job1", "job1"); err != nil {
+		t.Fatal(err)
+	}
+	for p := 1; p < N; p++ {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != "job"+strconv.Itoa(p) {
+			t.Fatalf("expected job%d, got %s", p, v)
+		}
 	}
 }
 
-func TestFHeapPush(t *testing.T) {
+func TestFHeapAdjustPriority(t *testing.T) {
 	h := intMinHeap[int]()
 	N := *HeapSize
-	for i := N; i > 0; i-- {
-		if err := Push(h, i, i*2, t.Name()); err != nil {
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
 			t.Fatal(err)
 		}
 	}
-	if size, err := h.Size(); err != nil {
-		t.Fatal(err)
-	} else if size != N {
-		t.Fatalf("expected size=1, got %d", size)
+	boost := func(p int) int { return p - N }
+	for v := N - 1; v >= 0; v -= 2 {
+		if err := h.AdjustPriority(v, boost); err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
 	}
-	expected := fmt.Sprintf("duplicate value=%v", N)
-	err := Push(h, N, 123123123, t.Name())
-	if err == nil {
-		t.Fatal("expected duplicate value error")
+	if err := h.AdjustPriority(0, func(p int) int { return p + 1 }); err == nil {
+		t.Fatal("expected error lowering priority")
+	}
+	if err := h.AdjustPriority(0, func(int) int { return math.MinInt }); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := h.AdjustPriority(N, boost); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
 	}
-	if actual := errors.Unwrap(err).Error(); actual != expected {
-		t.Fatalf("expected %q, got %q", expected, actual)
+	prev := math.MinInt
+	for h.Len() > 0 {
+		v, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p < prev {
+			t.Fatalf("popped priority %d after %d", p, prev)
+		}
+		if boosted := (N-1-v)%2 == 0; boosted != (p < 0) {
+			t.Fatalf("value %d has priority %d", v, p)
+		}
+		prev = p
 	}
 }
 
-func TestFHeapPop_OneInOneOut(t *testing.T) {
-	h := intMinHeap[int]()
-	v := 34
-	if err := Push(h, v, 12, t.Name()); err != nil {
-		t.Fatal(err)
+func TestFHeapFix(t *testing.T) {
+	type job struct{ deadline int }
+	h := New[*job, *job](func(x, y *job) bool { return x.deadline < y.deadline }, &job{math.MinInt})
+	N := *HeapSize
+	jobs := make([]*job, N)
+	for i, p := range rand.Perm(N) {
+		jobs[i] = &job{p}
+		if err := Push(h, jobs[i], jobs[i], t.Name()); err != nil {
+			t.Fatal(err)
+		}
 	}
-	if actual, err := Pop(h, t.Name()); err != nil {
+	if _, err := Pop(h, t.Name()); err != nil {
 		t.Fatal(err)
-	} else if actual != v {
-		t.Fatalf("expected highest-priority value to be %d, got %d", v, actual)
 	}
-	if size, err := h.Size(); err != nil {
-		t.Fatal(err)
-	} else if size != 0 {
-		t.Fatalf("expected empty heap, got size=%d", size)
+	for _, i := range rand.Perm(N) {
+		if !h.Contains(jobs[i]) {
+			continue
+		}
+		jobs[i].deadline = rand.Intn(2 * N)
+		if err := h.Fix(jobs[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
 	}
-}
-
-func TestFHeapPop_RandomPermutation(t *testing.T) {
-	h := intMinHeap[int]()
-	N := *HeapSize
-	perm := rand.Perm(N)
-	for _, p := range perm {
-		if err := Push(h, p, p, t.Name()); err != nil {
+	prev := math.MinInt
+	for h.Len() > 0 {
+		j, err := Pop(h, t.Name())
+		if err != nil {
 			t.Fatal(err)
 		}
+		if j.deadline < prev {
+			t.Fatalf("popped deadline %d after %d", j.deadline, prev)
+		}
+		prev = j.deadline
 	}
-	for expected := 0; expected < N; expected++ {
-		if actual, err := Pop(h, t.Name()); err != nil {
+	if err := h.Fix(jobs[0]); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
+	}
+}
+
+func TestFHeapPeekNode(t *testing.T) {
+	h := New[string, int](func(x, y int) bool { return x < y }, math.MinInt,
+		WithPushTimestamps[string, int](),
+		WithDuplicatePolicy[string, int](DuplicatesReplace))
+	if _, err := h.PeekNode(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	before := time.Now()
+	for i, v := range []string{"a", "b", "c", "d"} {
+		if err := Push(h, v, i, t.Name()); err != nil {
 			t.Fatal(err)
-		} else if actual != expected {
-			t.Fatalf("[i=%[1]d] expected value=%[1]d, got %[2]d", expected, actual)
 		}
 	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	view, err := h.PeekNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Value != "b" || view.Priority != 1 || view.Degree != 1 {
+		t.Fatalf("unexpected view %+v", view)
+	}
+	if view.PushedAt.Before(before) || view.PushedAt.After(time.Now()) {
+		t.Fatalf("unexpected push timestamp %v", view.PushedAt)
+	}
+	if view.Meta != nil {
+		t.Fatalf("expected no metadata, got %v", view.Meta)
+	}
+	if err := h.SetMeta("b", "retry"); err != nil {
+		t.Fatal(err)
+	}
+	if view, err = h.PeekNode(); err != nil || view.Meta != "retry" {
+		t.Fatalf("expected metadata retry, got %v (%v)", view.Meta, err)
+	}
+	if err := Push(h, "b", 10, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if !h.values["b"].pushedAt().Equal(view.PushedAt) {
+		t.Fatal("expected repositioning to keep the push timestamp")
+	}
 }
 
 func TestFHeapIncreasePriority(t *testing.T) {
@@ -236,7 +1117,7 @@ func TestFHeapIncreasePriority(t *testing.T) {
 			0,
 			[]int{9},
 			[]int{2},
-			[]error{errors.New("old priority 1 is higher than new 2")},
+			[]error{errors.New("IncreasePriority 9 (priority 2): priority lower than current 1")},
 		},
 		{
 			"7 nodes, 1 pop, increase lowest priority to highest",
@@ -297,6 +1178,34 @@ func TestFHeapIncreasePriority(t *testing.T) {
 	}
 }
 
+func TestFHeapIncreasePriorityPrev(t *testing.T) {
+	h := intMinHeap[string]()
+	if err := Push(h, "a", 10, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := Push(h, "b", 20, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := h.IncreasePriorityPrev("b", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev != 20 {
+		t.Fatalf("expected previous priority=20, got %d", prev)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	} else if v != "b" {
+		t.Fatalf("expected value=b, got %s", v)
+	}
+	if _, err := h.IncreasePriorityPrev("b", 1); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
+	}
+}
+
 func TestFHeapDelete(t *testing.T) {
 	type testcase struct {
 		name           string
@@ -385,3 +1294,274 @@ func TestFHeapDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestFHeapNewUnreserved(t *testing.T) {
+	h := NewUnreserved[int, int](func(x, y int) bool { return x < y })
+	priorities := []int{0, math.MinInt, 5, math.MaxInt, -5}
+	for i, p := range priorities {
+		if err := Push(h, i, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := IncreasePriority(h, 2, math.MinInt, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Delete(0); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []int{1, 2, 4, 3} {
+		if actual, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if actual != expected {
+			t.Fatalf("expected value=%d, got %d", expected, actual)
+		}
+	}
+}
+
+func TestFHeapCapacityHint(t *testing.T) {
+	N := *HeapSize
+	h := New(func(x, y int) bool { return x < y }, math.MinInt, WithCapacityHint[int, int](N), WithFIFOTies[int, int]())
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p/2, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	if h := New(func(x, y int) bool { return x < y }, math.MinInt, WithCapacityHint[int, int](-1)); h.Push(0, 0) != nil {
+		t.Fatal("expected a negative capacity hint to be ignored")
+	}
+}
+
+func TestFHeapReserve(t *testing.T) {
+	N := *HeapSize
+	h := intMinHeap[int]()
+	for v := range N / 2 {
+		if err := Push(h, v, v, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Reserve(N); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Reserve(-1); err != nil {
+		t.Fatal(err)
+	}
+	if n := h.Len(); n != N/2 {
+		t.Fatalf("expected size=%d, got %d", N/2, n)
+	}
+	for v := N / 2; v < N; v++ {
+		if err := Push(h, v, v, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	var nilHeap *Heap[int, int]
+	if err := nilHeap.Reserve(N); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+}
+
+func TestFHeapDeleteDirect(t *testing.T) {
+	N := *HeapSize
+	h := intMinHeap[int]()
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// deleting a value other than the highest-priority one doesn't
+	// consolidate the heap
+	if err := h.Delete(N - 1); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := h.Stats(); stats.Roots != N-1 {
+		t.Fatalf("expected %d roots, got %d", N-1, stats.Roots)
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	deleted := map[int]bool{0: true, N - 1: true}
+	for _, v := range rand.Perm(N)[:N/2] {
+		if deleted[v] {
+			continue
+		}
+		if err := h.Delete(v); err != nil {
+			t.Fatal(err)
+		}
+		deleted[v] = true
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for expected := range N {
+		if deleted[expected] {
+			continue
+		}
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
+
+func TestFHeapProbe(t *testing.T) {
+	// the lowest priority passed as highestPriority
+	h := New[int, int](func(x, y int) bool { return x < y }, math.MaxInt)
+	if err := h.Push(0, 0); !errors.Is(err, ErrMisconfigured) {
+		t.Fatalf("expected %v, got %v", ErrMisconfigured, err)
+	}
+	if n := h.Len(); n != 0 {
+		t.Fatalf("expected nothing to be pushed, got size=%d", n)
+	}
+	reflexive := New[int, int](func(x, y int) bool { return x <= y }, math.MinInt)
+	if err := reflexive.Push(0, 0); !errors.Is(err, ErrMisconfigured) {
+		t.Fatalf("expected %v, got %v", ErrMisconfigured, err)
+	}
+	h = intMinHeap[int]()
+	if err := h.Push(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !h.probed {
+		t.Fatal("expected the first push to probe the reserved priority")
+	}
+	unreserved := NewUnreserved[int, int](func(x, y int) bool { return x < y })
+	if err := unreserved.Push(0, math.MaxInt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFHeapDeepCascade(t *testing.T) {
+	// a chain of bereaved nodes, each the only child of the previous one,
+	// is cut entirely by increasing the priority of its deepest node
+	const depth = 100_000
+	items := make([]Item[int, int], depth)
+	for v := range depth {
+		items[v] = Item[int, int]{v, v}
+	}
+	h := intMinHeap[int]()
+	if err := h.PushAll(items); err != nil {
+		t.Fatal(err)
+	}
+	for v := depth - 1; v > 0; v-- {
+		y, x := h.values[v], h.values[v-1]
+		if err := h.link(y, x); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for v := 1; v < depth-1; v++ {
+		h.values[v].bereaved = true
+		h.marked++
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.IncreasePriority(depth-1, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	stats, _ := h.Stats()
+	if stats.Roots != depth || stats.Marked != 0 {
+		t.Fatalf("expected %d unmarked roots, got %d roots and %d marked", depth, stats.Roots, stats.Marked)
+	}
+	for i := range 3 {
+		expected := i - 1
+		if i == 0 {
+			expected = depth - 1
+		}
+		if popped, err := h.Pop(); err != nil {
+			t.Fatal(err)
+		} else if popped != expected {
+			t.Fatalf("expected %d, got %d", expected, popped)
+		}
+	}
+}
+
+func TestFHeapScratchReuse(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	scratch := h.scratch
+	if h.maxDegree > degreeBound(N-1) {
+		t.Fatalf("expected a degree bound of at most %d, got %d", degreeBound(N-1), h.maxDegree)
+	}
+	if cap(scratch) < h.maxDegree+1 {
+		t.Fatalf("expected a scratch table of at least %d, got %d", h.maxDegree+1, cap(scratch))
+	}
+	for range N / 2 {
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if &h.scratch[0] != &scratch[0] {
+		t.Fatal("expected the scratch table to be reused")
+	}
+	for _, x := range h.scratch[:cap(h.scratch)] {
+		if x != nil {
+			t.Fatalf("expected the scratch table to be cleared, found %v", x.value)
+		}
+	}
+}
+
+func TestFHeapPopAllocs(t *testing.T) {
+	if debug {
+		t.Skip("invariant assertions allocate under fheapdebug")
+	}
+	lessThan := func(x, y int) bool { return x < y }
+	cases := []struct {
+		name string
+		opts []Option[int, int]
+	}{
+		{"Default", nil},
+		{"FIFOTies", []Option[int, int]{WithFIFOTies[int, int]()}},
+		{"WithoutIndex", []Option[int, int]{WithoutIndex[int, int]()}},
+		{"EagerLinking", []Option[int, int]{WithEagerLinking[int, int](8)}},
+	}
+	N := 1 << 12
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := New(lessThan, math.MinInt, c.opts...)
+			for _, p := range rand.Perm(2 * N) {
+				if err := h.Push(p, p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// the first pop grows the scratch table
+			if _, err := h.Pop(); err != nil {
+				t.Fatal(err)
+			}
+			if allocs := testing.AllocsPerRun(N, func() { h.Pop() }); allocs != 0 {
+				t.Fatalf("expected Pop not to allocate, got %v allocations", allocs)
+			}
+		})
+	}
+	// with pooled nodes, pushing and popping in a loop doesn't allocate
+	h := New(lessThan, math.MinInt, WithNodePool[int, int]())
+	for v := range N {
+		if err := h.Push(v, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := N
+	allocs := testing.AllocsPerRun(N, func() {
+		h.Push(v, v)
+		h.Pop()
+		v++
+	})
+	if allocs != 0 {
+		t.Fatalf("expected Push and Pop not to allocate, got %v allocations", allocs)
+	}
+}
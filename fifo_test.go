@@ -0,0 +1,86 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapFIFOTies(t *testing.T) {
+	h := New[int, int](func(x, y int) bool { return x < y }, math.MinInt, WithFIFOTies[int, int]())
+	N := *HeapSize
+	const levels = 4
+	for v := 0; v < N; v++ {
+		if err := Push(h, v, v%levels, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// interleave pops and pushes to exercise consolidation
+	for v := N; v < 2*N; v++ {
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+		if err := Push(h, v, levels+rand.Intn(levels), t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last := map[int]int{}
+	prev := math.MinInt
+	for h.Len() > 0 {
+		v, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p < prev {
+			t.Fatalf("popped priority %d after %d", p, prev)
+		}
+		if l, ok := last[p]; ok && v < l {
+			t.Fatalf("priority %d: popped %d after %d", p, v, l)
+		}
+		last[p], prev = v, p
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFHeapPriorityEqual(t *testing.T) {
+	const eps = 1e-6
+	calls := 0
+	equal := func(a, b float64) bool {
+		calls++
+		return math.Abs(a-b) <= eps
+	}
+	h := New[int, float64](func(x, y float64) bool { return x < y-eps }, 0,
+		WithPriorityEqual[int](equal), WithFIFOTies[int, float64]())
+	if err := h.Push(-1, eps/2); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	N := *HeapSize
+	const levels = 4
+	for v := 0; v < N; v++ {
+		jitter := (rand.Float64() - 0.5) * eps
+		if err := Push(h, v, float64(1+v%levels)+jitter, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls == 0 {
+		t.Fatal("expected the priority equality function to be used")
+	}
+	last := map[int]int{}
+	prev := math.Inf(-1)
+	for h.Len() > 0 {
+		v, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p < prev-eps {
+			t.Fatalf("popped priority %v after %v", p, prev)
+		}
+		level := v % levels
+		if l, ok := last[level]; ok && v < l {
+			t.Fatalf("level %d: popped %d after %d", level, v, l)
+		}
+		last[level], prev = v, p
+	}
+}
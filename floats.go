@@ -0,0 +1,58 @@
+package fheap
+
+import "errors"
+
+var ErrNaNPriority = errors.New("NaN priority")
+
+// NaNPolicy determines how the comparison functions returned by FloatMin
+// and FloatMax order NaN priorities, which `<` doesn't order at all. A
+// comparison function can't reject NaN, so heaps which mustn't hold NaN
+// should validate priorities with RejectNaN instead.
+type NaNPolicy int
+
+const (
+	// NaNLowest orders NaN below every other priority.
+	NaNLowest NaNPolicy = iota
+	// NaNHighest orders NaN above every other priority. The highest
+	// priority, to be reserved by New, is then NaN.
+	NaNHighest
+)
+
+// FloatMin returns a comparison function for min-heaps of floating-point
+// priorities, ordering NaN according to `nan`. NaNs are equal to each
+// other. Unless NaN is highest, the highest priority is -Inf.
+func FloatMin[P ~float32 | ~float64](nan NaNPolicy) func(x, y P) bool {
+	return floatHigherThan(func(x, y P) bool { return x < y }, nan)
+}
+
+// FloatMax returns a comparison function for max-heaps of floating-point
+// priorities, ordering NaN according to `nan`. NaNs are equal to each
+// other. Unless NaN is highest, the highest priority is +Inf.
+func FloatMax[P ~float32 | ~float64](nan NaNPolicy) func(x, y P) bool {
+	return floatHigherThan(func(x, y P) bool { return x > y }, nan)
+}
+
+// floatHigherThan extends a comparison function of non-NaN priorities to
+// NaN.
+func floatHigherThan[P ~float32 | ~float64](higherThan func(x, y P) bool, nan NaNPolicy) func(x, y P) bool {
+	return func(x, y P) bool {
+		xNaN, yNaN := x != x, y != y
+		switch {
+		case !xNaN && !yNaN:
+			return higherThan(x, y)
+		case nan == NaNHighest:
+			return xNaN && !yNaN
+		default:
+			return !xNaN && yNaN
+		}
+	}
+}
+
+// RejectNaN is a priority validator rejecting NaN with ErrNaNPriority, for
+// use with WithPriorityValidator.
+func RejectNaN[P ~float32 | ~float64](priority P) error {
+	if priority != priority {
+		return ErrNaNPriority
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFloatComparators(t *testing.T) {
+	nan := math.NaN()
+	cases := []struct {
+		name       string
+		higherThan func(x, y float64) bool
+		highest    float64
+		popped     []float64
+	}{
+		{"FloatMin/NaNLowest", FloatMin[float64](NaNLowest), math.Inf(-1), []float64{-1, 0, 2, math.Inf(1), nan, nan}},
+		{"FloatMin/NaNHighest", FloatMin[float64](NaNHighest), nan, []float64{math.Inf(-1), -1, 0, 2, math.Inf(1)}},
+		{"FloatMax/NaNLowest", FloatMax[float64](NaNLowest), math.Inf(1), []float64{2, 0, -1, math.Inf(-1), nan, nan}},
+		{"FloatMax/NaNHighest", FloatMax[float64](NaNHighest), nan, []float64{math.Inf(1), 2, 0, -1, math.Inf(-1)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := New[int](c.higherThan, c.highest)
+			if err := h.Push(-1, c.highest); err != ErrReservedPriority {
+				t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+			}
+			for i, j := range rand.Perm(len(c.popped)) {
+				if err := Push(h, i, c.popped[j], t.Name()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, expected := range c.popped {
+				_, p, err := h.PopPair()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p != expected && !(math.IsNaN(p) && math.IsNaN(expected)) {
+					t.Fatalf("expected %v, got %v", expected, p)
+				}
+			}
+		})
+	}
+}
+
+func TestFHeapRejectNaN(t *testing.T) {
+	h := New[string](FloatMin[float64](NaNLowest), math.Inf(-1), WithPriorityValidator[string](RejectNaN[float64]))
+	if err := h.Push("a", math.NaN()); err != ErrNaNPriority {
+		t.Fatalf("expected %v, got %v", ErrNaNPriority, err)
+	}
+	if err := h.Push("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.IncreasePriority("a", math.NaN()); err != ErrNaNPriority {
+		t.Fatalf("expected %v, got %v", ErrNaNPriority, err)
+	}
+	if err := h.PushAll([]Item[string, float64]{{"b", 2}, {"c", math.NaN()}}); !errors.Is(err, ErrNaNPriority) {
+		t.Fatalf("expected %v, got %v", ErrNaNPriority, err)
+	}
+	if n := h.Len(); n != 1 {
+		t.Fatalf("expected size=1, got %d", n)
+	}
+}
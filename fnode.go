@@ -1,8 +1,8 @@
 package fheap
 
 import (
-	"errors"
 	"fmt"
+	"time"
 )
 
 // fnode is a Fibonacci heap node, consisting of a:
@@ -11,26 +11,81 @@ import (
 //   - bereavement flag
 //   - parent, children, left, right node pointers
 //   - degree
+//   - insertion sequence number
+//   - optional fields of opt-in features
+//   - next node holding the same value, in heaps allowing duplicates
+//   - heap holding the node, to check handles against
 //
 // fnode siblings are doubly-linked.
 // Since fnodes are only used by fheaps, the implemented
 // methods are, wlog, left-centric.
-type fnode[V, P any] struct {
-	Value                         V
+type fnode[V comparable, P any] struct {
+	value                         V
 	priority                      P
 	bereaved                      bool
 	parent, children, left, right *fnode[V, P]
 	degree                        int
+	seq                           uint64
+	extras                        *fnodeExtras
+	twin                          *fnode[V, P]
+	owner                         *Heap[V, P]
 }
 
-var errNilFnode = errors.New("nil node")
-var errBarrenFnode = errors.New("barren node")
+// fnodeExtras holds the fields of a node used by opt-in features, which is
+// only allocated once one of them is set, so that nodes of heaps not using
+// those features stay small. It consists of a:
+//   - push timestamp, if recorded
+//   - expiry time, if any
+//   - user metadata, if any
+type fnodeExtras struct {
+	pushedAt  time.Time
+	expiresAt time.Time
+	meta      any
+}
+
+// extra returns the node's optional fields, allocating them if need be.
+func (fn *fnode[V, P]) extra() *fnodeExtras {
+	if fn.extras == nil {
+		fn.extras = &fnodeExtras{}
+	}
+	return fn.extras
+}
+
+// pushedAt returns when the node was pushed, if recorded.
+func (fn *fnode[V, P]) pushedAt() time.Time {
+	if fn.extras == nil {
+		return time.Time{}
+	}
+	return fn.extras.pushedAt
+}
+
+// expiresAt returns when the node expires, if it does.
+func (fn *fnode[V, P]) expiresAt() time.Time {
+	if fn.extras == nil {
+		return time.Time{}
+	}
+	return fn.extras.expiresAt
+}
+
+// meta returns the node's user metadata, if any.
+func (fn *fnode[V, P]) meta() any {
+	if fn.extras == nil {
+		return nil
+	}
+	return fn.extras.meta
+}
+
+// Node errors only arise from corrupted node pointers, so they wrap
+// ErrCorrupted.
+var errNilFnode = fmt.Errorf("%w: nil node", ErrCorrupted)
+var errBarrenFnode = fmt.Errorf("%w: barren node", ErrCorrupted)
+var errUnrelatedFnode = fmt.Errorf("%w: unrelated node", ErrCorrupted)
 
 // newFnode creates a new fnode given a priority and a value.
 // The node's parent and children pointers are nil, and its
 // left and right pointers are set to itself.
-func newFnode[V, P any](value V, priority P) *fnode[V, P] {
-	f := &fnode[V, P]{priority: priority, Value: value}
+func newFnode[V comparable, P any](value V, priority P) *fnode[V, P] {
+	f := &fnode[V, P]{priority: priority, value: value}
 	f.left = f
 	f.right = f
 	return f
@@ -48,6 +103,20 @@ func (fn *fnode[V, P]) insertLeft(other *fnode[V, P]) error {
 	return nil
 }
 
+// splice joins another fnode's sibling ring to the left of the current
+// fnode, such that the rings form a single ring.
+func (fn *fnode[V, P]) splice(other *fnode[V, P]) error {
+	if fn == nil || other == nil {
+		return errNilFnode
+	}
+	last := other.left
+	fn.left.right = other
+	other.left = fn.left
+	last.right = fn
+	fn.left = last
+	return nil
+}
+
 // insertChild inserts an fnode to the left of the current fnode's
 // children pointer.
 // The child fnode's parent pointer is updated, and the parent fnode's
@@ -79,7 +148,7 @@ func (fn *fnode[V, P]) removeChild(child *fnode[V, P]) error {
 		return errBarrenFnode
 	}
 	if child.parent != fn {
-		return fmt.Errorf("child %v is unrelated to node %v", child, fn)
+		return fmt.Errorf("%w: child %v of %v", errUnrelatedFnode, child.value, fn.value)
 	}
 	fn.degree--
 	if fn.children == child {
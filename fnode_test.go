@@ -3,6 +3,7 @@ package fheap
 import (
 	"flag"
 	"testing"
+	"unsafe"
 )
 
 var ListSize = flag.Int("listsize", 100, "size of arbitrary list when testing")
@@ -18,7 +19,7 @@ func TestFNodeInsertLeft(t *testing.T) {
 	// walk left to right
 	counter := 0
 	for iter := f; ; iter = iter.right {
-		val := iter.Value
+		val := iter.value
 		if val != counter {
 			t.Fatalf("expected %d, got %d", counter, val)
 		}
@@ -30,7 +31,7 @@ func TestFNodeInsertLeft(t *testing.T) {
 	// walk right to left
 	counter = N
 	for iter := f.left; ; iter = iter.left {
-		val := iter.Value
+		val := iter.value
 		if val != counter {
 			t.Fatalf("expected %d, got %d", counter, val)
 		}
@@ -56,7 +57,7 @@ func TestFNodeInsertChild(t *testing.T) {
 	// walk children left to right
 	counter := 1
 	for iter := f.children; ; iter = iter.right {
-		val := iter.Value
+		val := iter.value
 		if val != counter {
 			t.Fatalf("expected %d, got %d", counter, val)
 		}
@@ -68,7 +69,7 @@ func TestFNodeInsertChild(t *testing.T) {
 	// walk children right to left
 	counter = N
 	for iter := f.children.left; ; iter = iter.left {
-		val := iter.Value
+		val := iter.value
 		if val != counter {
 			t.Fatalf("expected %d, got %d", counter, val)
 		}
@@ -95,9 +96,75 @@ func TestFNodePopChild(t *testing.T) {
 			}
 			t.Fatal(err)
 		}
-		val := child.Value
+		val := child.value
 		if val != counter {
 			t.Fatalf("expected %d, got %d", counter, val)
 		}
 	}
 }
+
+func TestFNodeSplice(t *testing.T) {
+	a := newFnode(0, 0)
+	b := newFnode(1, 1)
+	N := *ListSize
+	for i := 1; i < N; i++ {
+		if err := a.insertLeft(newFnode(2*i, 2*i)); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.insertLeft(newFnode(2*i+1, 2*i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.splice(b); err != nil {
+		t.Fatal(err)
+	}
+	expected := make([]int, 0, 2*N)
+	for i := 0; i < N; i++ {
+		expected = append(expected, 2*i)
+	}
+	for i := 0; i < N; i++ {
+		expected = append(expected, 2*i+1)
+	}
+	// walk left to right
+	i := 0
+	for iter := a; ; iter = iter.right {
+		if iter.value != expected[i] {
+			t.Fatalf("expected %d, got %d", expected[i], iter.value)
+		}
+		if iter.right.left != iter {
+			t.Fatalf("inconsistent siblings at %d", iter.value)
+		}
+		i++
+		if iter.right == a {
+			break
+		}
+	}
+	if i != 2*N {
+		t.Fatalf("expected %d nodes, got %d", 2*N, i)
+	}
+}
+
+func TestFNodeExtras(t *testing.T) {
+	h := intMinHeap[int]()
+	if err := h.Push(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if h.values[0].extras != nil {
+		t.Fatal("expected no optional fields without opt-in features")
+	}
+	if err := h.SetMeta(0, "meta"); err != nil {
+		t.Fatal(err)
+	}
+	if x := h.values[0]; x.meta() != "meta" || !x.pushedAt().IsZero() || !x.expiresAt().IsZero() {
+		t.Fatalf("unexpected optional fields %+v", *x.extras)
+	}
+}
+
+func TestFNodeSize(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("sizes are checked on 64-bit platforms")
+	}
+	if size := unsafe.Sizeof(fnode[int, int]{}); size > 96 {
+		t.Fatalf("expected fnode[int, int] to take at most 96 bytes, got %d", size)
+	}
+}
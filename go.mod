@@ -1,3 +1,3 @@
 module github.com/iyassou/fibonacci-heap
 
-go 1.20
+go 1.24
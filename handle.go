@@ -0,0 +1,116 @@
+package fheap
+
+import "errors"
+
+var ErrNilHandle = errors.New("nil handle")
+var ErrForeignHandle = errors.New("handle to another heap's value")
+
+// Entry is a read-only view of an element of a heap.
+type Entry[V, P any] interface {
+	Value() V
+	Priority() P
+}
+
+var _ Entry[int, int] = (*Handle[int, int])(nil)
+
+// Handle is an opaque reference to a value's node in a heap, letting its
+// priority be updated without looking the value up.
+// A handle goes stale once its value is removed from the heap.
+type Handle[V comparable, P any] struct {
+	node *fnode[V, P]
+}
+
+// Value returns the handle's value.
+func (h *Handle[V, P]) Value() V {
+	return h.node.value
+}
+
+// Priority returns the handle's value's priority.
+func (h *Handle[V, P]) Priority() P {
+	return h.node.priority
+}
+
+// Stale reports whether the handle's value has been removed from the heap.
+func (h *Handle[V, P]) Stale() bool {
+	return h.node.left == nil
+}
+
+// PushHandle behaves like Push, additionally returning a handle to the
+// value's node. The handle is stale if the value was dropped to bound the
+// heap's size. Heaps created with WithNodePool or WithoutIndex don't
+// support handles.
+func (fh *Heap[V, P]) PushHandle(value V, priority P) (*Handle[V, P], error) {
+	if fh != nil && fh.pool != nil {
+		return nil, ErrUnsupported
+	}
+	if fh != nil && fh.unindexed {
+		return nil, errUnindexed
+	}
+	if err := fh.Push(value, priority); err != nil {
+		return nil, err
+	}
+	if fh.intern != nil {
+		value = fh.intern(value)
+	}
+	node, ok := fh.values[value]
+	if !ok {
+		// the value was dropped to bound the heap's size
+		return &Handle[V, P]{&fnode[V, P]{value: value, priority: priority}}, nil
+	}
+	return &Handle[V, P]{node}, nil
+}
+
+// IncreasePriorityHandle increases the priority of a handle's value.
+// ErrForeignHandle is returned if the value is in another heap.
+func (fh *Heap[V, P]) IncreasePriorityHandle(h *Handle[V, P], priority P) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if h == nil {
+		return ErrNilHandle
+	}
+	if h.Stale() {
+		return &OpError[V, P]{"IncreasePriority", h.node.value, priority, ErrValueNotFound}
+	}
+	if h.node.owner != fh {
+		return &OpError[V, P]{"IncreasePriority", h.node.value, priority, ErrForeignHandle}
+	}
+	if err := fh.checkPriority(priority); err != nil {
+		return err
+	}
+	fh.diagnostics.record("IncreasePriority", h.node.value, priority)
+	return fh.increaseNode(h.node, priority)
+}
+
+// DeleteHandle deletes a handle's value from the heap.
+// ErrForeignHandle is returned if the value is in another heap.
+func (fh *Heap[V, P]) DeleteHandle(h *Handle[V, P]) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if h == nil {
+		return ErrNilHandle
+	}
+	if h.Stale() {
+		return &OpError[V, P]{"Delete", h.node.value, h.node.priority, ErrValueNotFound}
+	}
+	if h.node.owner != fh {
+		return &OpError[V, P]{"Delete", h.node.value, h.node.priority, ErrForeignHandle}
+	}
+	fh.diagnostics.record("Delete", h.node.value, h.node.priority)
+	return fh.deleteNode(h.node)
+}
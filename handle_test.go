@@ -0,0 +1,92 @@
+package fheap
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapHandles(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	handles := make([]*Handle[int, int], N)
+	for _, p := range rand.Perm(N) {
+		handle, err := h.PushHandle(p, p+N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handles[p] = handle
+	}
+	// reverse the order by decreasing keys through the handles
+	for v, handle := range handles {
+		if handle.Value() != v || handle.Priority() != v+N {
+			t.Fatalf("expected (%d, %d), got (%d, %d)", v, v+N, handle.Value(), handle.Priority())
+		}
+		if err := h.IncreasePriorityHandle(handle, N-v); err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for v := 0; v < N; v += 2 {
+		if err := h.DeleteHandle(handles[v]); err != nil {
+			t.Fatal(err)
+		}
+		if !handles[v].Stale() {
+			t.Fatalf("expected handle of deleted value %d to be stale", v)
+		}
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	expected := N - 1
+	if expected%2 == 0 {
+		expected--
+	}
+	for ; expected > 0; expected -= 2 {
+		v, err := Pop(h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+		if !handles[v].Stale() {
+			t.Fatalf("expected handle of popped value %d to be stale", v)
+		}
+	}
+	if err := h.IncreasePriorityHandle(handles[0], 0); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
+	}
+	if err := h.DeleteHandle(nil); !errors.Is(err, ErrNilHandle) {
+		t.Fatalf("expected %v, got %v", ErrNilHandle, err)
+	}
+}
+
+func TestFHeapForeignHandles(t *testing.T) {
+	h, other := intMinHeap[int](), intMinHeap[int]()
+	handle, err := h.PushHandle(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.IncreasePriorityHandle(handle, 0); !errors.Is(err, ErrForeignHandle) {
+		t.Fatalf("expected %v, got %v", ErrForeignHandle, err)
+	}
+	if err := other.DeleteHandle(handle); !errors.Is(err, ErrForeignHandle) {
+		t.Fatalf("expected %v, got %v", ErrForeignHandle, err)
+	}
+	// melding moves the value, and its handle, to the other heap
+	if err := other.Meld(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DeleteHandle(handle); !errors.Is(err, ErrForeignHandle) {
+		t.Fatalf("expected %v, got %v", ErrForeignHandle, err)
+	}
+	if err := other.DeleteHandle(handle); err != nil {
+		t.Fatal(err)
+	}
+	if size, _ := other.Size(); size != 0 {
+		t.Fatalf("expected an empty heap, got size=%d", size)
+	}
+}
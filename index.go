@@ -0,0 +1,160 @@
+package fheap
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+)
+
+// errUnindexed is returned by operations needing to find a value's node in
+// a heap created with WithoutIndex.
+var errUnindexed = fmt.Errorf("%w: heap doesn't index its values", ErrUnsupported)
+
+// errMultiset is returned by operations tracking changes by value in a heap
+// created with WithAllowDuplicates.
+var errMultiset = fmt.Errorf("%w: heap allows duplicate values", ErrUnsupported)
+
+// WithoutIndex makes the heap skip its value index, sparing workloads which
+// only push and pop, such as heapsort or k-way merging, a map insertion and
+// deletion per value. Without the index, values can't be found by value, so
+// that operations such as IncreasePriority, Delete, SetMeta and DiffSince
+// return ErrUnsupported, Contains reports no value as present, and values
+// may be pushed more than once.
+func WithoutIndex[V comparable, P any]() Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.unindexed = true
+		fh.values = nil
+	}
+}
+
+// allowsDuplicates reports whether a value may be in the heap more than
+// once.
+func (fh *Heap[V, P]) allowsDuplicates() bool {
+	return fh.unindexed || fh.duplicates == DuplicatesAllow
+}
+
+// collides reports whether pushing a value would run into an occurrence
+// already in the heap, rather than add another one.
+func (fh *Heap[V, P]) collides(value V) bool {
+	_, ok := fh.values[value]
+	return ok && !fh.allowsDuplicates()
+}
+
+// len returns the number of values in the heap.
+func (fh *Heap[V, P]) len() int {
+	if fh.allowsDuplicates() {
+		return fh.count
+	}
+	return len(fh.values)
+}
+
+// index records a value's node added to the heap. In heaps allowing
+// duplicates, the node is chained in front of the value's other nodes.
+func (fh *Heap[V, P]) index(value V, x *fnode[V, P]) {
+	if fh.allowsDuplicates() {
+		fh.count++
+	}
+	if fh.unindexed {
+		return
+	}
+	if fh.duplicates == DuplicatesAllow {
+		x.twin = fh.values[value]
+	}
+	fh.values[value] = x
+}
+
+// unindex forgets a node removed from the heap.
+func (fh *Heap[V, P]) unindex(x *fnode[V, P]) {
+	if fh.allowsDuplicates() {
+		fh.count--
+	}
+	if fh.unindexed {
+		return
+	}
+	if x.twin == nil && fh.values[x.value] == x {
+		delete(fh.values, x.value)
+		return
+	}
+	if y := fh.values[x.value]; y == x {
+		fh.values[x.value] = x.twin
+	} else {
+		for ; y != nil; y = y.twin {
+			if y.twin == x {
+				y.twin = x.twin
+				break
+			}
+		}
+	}
+	x.twin = nil
+}
+
+// indexes reports whether a node is indexed by its value.
+func (fh *Heap[V, P]) indexes(x *fnode[V, P]) bool {
+	for y := fh.values[x.value]; y != nil; y = y.twin {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
+
+// newIndex replaces the heap's index with an empty one sized for `n`
+// values.
+func (fh *Heap[V, P]) newIndex(n int) {
+	fh.count = 0
+	if !fh.unindexed {
+		fh.values = make(map[V]*fnode[V, P], n)
+	}
+}
+
+// resetIndex forgets every node in the heap.
+func (fh *Heap[V, P]) resetIndex() {
+	fh.count = 0
+	clear(fh.values)
+}
+
+// nodes iterates over the heap's nodes in no particular order. Heaps
+// without a value index are walked tree by tree, with an explicit stack
+// since trees can be deep, and a node's links are read before it's yielded,
+// so that they may be cleared.
+func (fh *Heap[V, P]) nodes() iter.Seq[*fnode[V, P]] {
+	if fh.duplicates == DuplicatesAllow && !fh.unindexed {
+		return func(yield func(*fnode[V, P]) bool) {
+			for _, x := range fh.values {
+				for x != nil {
+					twin := x.twin
+					if !yield(x) {
+						return
+					}
+					x = twin
+				}
+			}
+		}
+	}
+	if !fh.unindexed {
+		return maps.Values(fh.values)
+	}
+	return func(yield func(*fnode[V, P]) bool) {
+		if fh.prioritaire == nil {
+			return
+		}
+		stack := []*fnode[V, P]{fh.prioritaire}
+		for len(stack) > 0 {
+			start := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for x := start; ; {
+				next := x.right
+				if x.children != nil {
+					stack = append(stack, x.children)
+				}
+				if !yield(x) {
+					return
+				}
+				if next == start {
+					break
+				}
+				x = next
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func unindexedHeap() *Heap[int, int] {
+	return New(func(x, y int) bool { return x < y }, math.MinInt, WithoutIndex[int, int]())
+}
+
+func TestFHeapWithoutIndex(t *testing.T) {
+	h := unindexedHeap()
+	N := *HeapSize
+	// every value is pushed twice, which the index would forbid
+	for range 2 {
+		for _, p := range rand.Perm(N) {
+			if err := Push(h, p, p, t.Name()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if h.values != nil {
+		t.Fatal("expected no value index")
+	}
+	if size, _ := h.Size(); size != 2*N {
+		t.Fatalf("expected size=%d, got %d", 2*N, size)
+	}
+	if h.Contains(0) {
+		t.Fatal("expected unindexed values to be reported missing")
+	}
+	for _, err := range []error{
+		h.IncreasePriority(0, -1),
+		h.Delete(0),
+		h.Fix(0),
+		h.UpdateValue(0, -1),
+		h.SetMeta(0, nil),
+	} {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+		}
+	}
+	if _, err := h.PushHandle(0, 0); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := h.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clone.Equal(h) {
+		t.Fatal("expected the clone to equal the heap")
+	}
+	for i := range 2 * N {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != i/2 {
+			t.Fatalf("expected %d, got %d", i/2, v)
+		}
+	}
+	if size, _ := h.Size(); size != 0 {
+		t.Fatalf("expected an empty heap, got size=%d", size)
+	}
+	if clone.Equal(h) {
+		t.Fatal("expected the clone to be unaffected by popping the heap")
+	}
+}
+
+func TestFHeapWithoutIndex_Bulk(t *testing.T) {
+	h := unindexedHeap()
+	if err := h.PushAll([]Item[int, int]{{1, 1}, {1, 1}, {2, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.PushSet(3, 3, 3); err != nil {
+		t.Fatal(err)
+	}
+	n, err := h.DeleteWhere(func(v, p int) bool { return v == 1 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 deletions, got %d", n)
+	}
+	evicted, err := h.EvictLowest(1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evicted != 1 {
+		t.Fatalf("expected 1 eviction, got %d", evicted)
+	}
+	other := unindexedHeap()
+	if err := other.Push(2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Meld(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []int{2, 2, 3} {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+	if size, _ := other.Size(); size != 0 {
+		t.Fatalf("expected the melded heap to be emptied, got size=%d", size)
+	}
+}
+
+func BenchmarkFHeapPushPop_WithoutIndex(b *testing.B) {
+	h := unindexedHeap()
+	perm := rand.Perm(1 << 10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range perm {
+			h.Push(p, p)
+		}
+		for range perm {
+			h.Pop()
+		}
+	}
+}
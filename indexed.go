@@ -0,0 +1,128 @@
+package fheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// IndexedPriorityQueue is a facade over an OrderedHeap matching the
+// textbook indexed minimum priority queue API, which associates keys with
+// integer indices in [0, maxN).
+type IndexedPriorityQueue[K cmp.Ordered] struct {
+	maxN int
+	heap *OrderedHeap[int, K]
+}
+
+// NewIndexedPriorityQueue creates an empty IndexedPriorityQueue for
+// indices in [0, maxN).
+func NewIndexedPriorityQueue[K cmp.Ordered](maxN int) *IndexedPriorityQueue[K] {
+	return &IndexedPriorityQueue[K]{maxN: maxN, heap: NewOrderedHeap[int, K]()}
+}
+
+// IsEmpty determines if the queue is empty.
+func (pq *IndexedPriorityQueue[K]) IsEmpty() bool {
+	return len(pq.heap.values) == 0
+}
+
+// Size returns the number of keys in the queue.
+func (pq *IndexedPriorityQueue[K]) Size() int {
+	return len(pq.heap.values)
+}
+
+// Contains determines if index i is associated with a key.
+func (pq *IndexedPriorityQueue[K]) Contains(i int) bool {
+	_, ok := pq.heap.values[i]
+	return ok
+}
+
+// Insert associates a key with index i.
+func (pq *IndexedPriorityQueue[K]) Insert(i int, key K) error {
+	if err := pq.validate(i); err != nil {
+		return err
+	}
+	return pq.heap.Push(i, key)
+}
+
+// MinIndex returns the index associated with the minimum key.
+func (pq *IndexedPriorityQueue[K]) MinIndex() (int, error) {
+	if pq.heap.prioritaire == nil {
+		return 0, ErrEmptyHeap
+	}
+	return pq.heap.prioritaire.value, nil
+}
+
+// MinKey returns the minimum key.
+func (pq *IndexedPriorityQueue[K]) MinKey() (key K, err error) {
+	if pq.heap.prioritaire == nil {
+		return key, ErrEmptyHeap
+	}
+	return pq.heap.prioritaire.priority, nil
+}
+
+// DelMin removes the minimum key, returning its associated index.
+func (pq *IndexedPriorityQueue[K]) DelMin() (int, error) {
+	return pq.heap.Pop()
+}
+
+// KeyOf returns the key associated with index i.
+func (pq *IndexedPriorityQueue[K]) KeyOf(i int) (key K, err error) {
+	if err = pq.validate(i); err != nil {
+		return
+	}
+	x, ok := pq.heap.values[i]
+	if !ok {
+		return key, fmt.Errorf("index %d missing from queue", i)
+	}
+	return x.priority, nil
+}
+
+// DecreaseKey decreases the key associated with index i.
+func (pq *IndexedPriorityQueue[K]) DecreaseKey(i int, key K) error {
+	if err := pq.validate(i); err != nil {
+		return err
+	}
+	return pq.heap.IncreasePriority(i, key)
+}
+
+// IncreaseKey increases the key associated with index i.
+func (pq *IndexedPriorityQueue[K]) IncreaseKey(i int, key K) error {
+	old, err := pq.KeyOf(i)
+	if err != nil {
+		return err
+	}
+	if key < old {
+		return fmt.Errorf("old key %v is greater than new %v", old, key)
+	}
+	return pq.ChangeKey(i, key)
+}
+
+// ChangeKey changes the key associated with index i.
+func (pq *IndexedPriorityQueue[K]) ChangeKey(i int, key K) error {
+	old, err := pq.KeyOf(i)
+	if err != nil {
+		return err
+	}
+	if key <= old {
+		return pq.heap.IncreasePriority(i, key)
+	}
+	if err := pq.heap.Delete(i); err != nil {
+		return err
+	}
+	return pq.heap.Push(i, key)
+}
+
+// Delete removes the key associated with index i.
+func (pq *IndexedPriorityQueue[K]) Delete(i int) error {
+	if err := pq.validate(i); err != nil {
+		return err
+	}
+	return pq.heap.Delete(i)
+}
+
+// validate checks index i is within range.
+func (pq *IndexedPriorityQueue[K]) validate(i int) error {
+	if i < 0 || i >= pq.maxN {
+		return fmt.Errorf("index %d out of range [0, %d)", i, pq.maxN)
+	}
+	return nil
+}
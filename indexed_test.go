@@ -0,0 +1,54 @@
+package fheap
+
+import "testing"
+
+func TestIndexedPriorityQueue(t *testing.T) {
+	keys := []string{"it", "was", "the", "best", "of", "times"}
+	pq := NewIndexedPriorityQueue[string](len(keys))
+	for i, key := range keys {
+		if err := pq.Insert(i, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pq.Insert(len(keys), "oob"); err == nil {
+		t.Fatal("expected out of range error")
+	}
+	if !pq.Contains(2) || pq.Contains(-1) {
+		t.Fatal("unexpected Contains result")
+	}
+	if err := pq.DecreaseKey(1, "age"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pq.IncreaseKey(3, "worst"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pq.DecreaseKey(0, "zzz"); err == nil {
+		t.Fatal("expected decreasing to a greater key to fail")
+	}
+	if key, err := pq.KeyOf(1); err != nil {
+		t.Fatal(err)
+	} else if key != "age" {
+		t.Fatalf("expected key=age, got %s", key)
+	}
+	if err := pq.Delete(4); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []int{1, 0, 2, 5, 3} {
+		if i, err := pq.MinIndex(); err != nil {
+			t.Fatal(err)
+		} else if i != expected {
+			t.Fatalf("expected min index=%d, got %d", expected, i)
+		}
+		if i, err := pq.DelMin(); err != nil {
+			t.Fatal(err)
+		} else if i != expected {
+			t.Fatalf("expected index=%d, got %d", expected, i)
+		}
+	}
+	if !pq.IsEmpty() || pq.Size() != 0 {
+		t.Fatal("expected empty queue")
+	}
+	if _, err := pq.DelMin(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+}
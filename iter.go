@@ -0,0 +1,68 @@
+package fheap
+
+import (
+	"iter"
+	"slices"
+)
+
+// All returns an iterator over the heap's values and their priorities, in
+// no particular order unless the heap was created with
+// WithDeterministicDump. The heap mustn't be modified during iteration.
+func (fh *Heap[V, P]) All() iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		if fh == nil {
+			return
+		}
+		for node := range fh.snapshot() {
+			if !yield(node.value, node.priority) {
+				return
+			}
+		}
+	}
+}
+
+// snapshot returns an iterator over the heap's nodes, ordered by priority
+// and then by insertion order if the heap's snapshots are deterministic, or
+// in no particular order otherwise.
+func (fh *Heap[V, P]) snapshot() iter.Seq[*fnode[V, P]] {
+	if !fh.deterministic {
+		return fh.nodes()
+	}
+	nodes := slices.Collect(fh.nodes())
+	slices.SortFunc(nodes, fh.compareNodes)
+	return slices.Values(nodes)
+}
+
+// Drain returns an iterator popping the heap's values and their priorities
+// in priority order until the heap is empty, or Pop fails. Values remain
+// in the heap if iteration is stopped early.
+func (fh *Heap[V, P]) Drain() iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		for fh.Len() > 0 {
+			value, priority, err := fh.PopPair()
+			if err != nil || !yield(value, priority) {
+				return
+			}
+		}
+	}
+}
+
+// PopWhile returns an iterator popping the heap's values and their
+// priorities in priority order for as long as `pred` holds for the
+// highest-priority value, and Pop doesn't fail. The value for which `pred`
+// doesn't hold is left in the heap, as are values remaining if iteration is
+// stopped early. Expired values are discarded before `pred` is tested, so
+// that it's only tested on the value to be popped.
+func (fh *Heap[V, P]) PopWhile(pred func(value V, priority P) bool) iter.Seq2[V, P] {
+	return func(yield func(V, P) bool) {
+		for {
+			value, priority, err := fh.Peek()
+			if err != nil || !pred(value, priority) {
+				return
+			}
+			if _, _, err := fh.pop(); err != nil || !yield(value, priority) {
+				return
+			}
+		}
+	}
+}
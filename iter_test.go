@@ -0,0 +1,172 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFHeapAll(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	for range nilHeap.All() {
+		t.Fatal("expected nil heap to yield nothing")
+	}
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, -p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	seen := map[int]bool{}
+	for v, p := range h.All() {
+		if p != -v {
+			t.Fatalf("expected value %d to have priority %d, got %d", v, -v, p)
+		}
+		seen[v] = true
+	}
+	if len(seen) != N {
+		t.Fatalf("expected %d values, got %d", N, len(seen))
+	}
+	count := 0
+	for range h.All() {
+		if count++; count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Fatalf("expected to break after 3 values, got %d", count)
+	}
+	if n := h.Len(); n != N {
+		t.Fatalf("expected All not to modify the heap, got size=%d", n)
+	}
+}
+
+func TestFHeapDrain(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	for range nilHeap.Drain() {
+		t.Fatal("expected nil heap to yield nothing")
+	}
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := 0
+	for v, p := range h.Drain() {
+		if v != expected || p != expected {
+			t.Fatalf("expected (%d, %d), got (%d, %d)", expected, expected, v, p)
+		}
+		if expected++; expected == N/2 {
+			break
+		}
+	}
+	if n := h.Len(); n != N-N/2 {
+		t.Fatalf("expected %d values left after breaking, got %d", N-N/2, n)
+	}
+	for v := range h.Drain() {
+		if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+		expected++
+	}
+	if expected != N || h.Len() != 0 {
+		t.Fatalf("expected heap to be drained, got %d values left", h.Len())
+	}
+}
+
+func TestFHeapPopWhile(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	due := N / 2
+	expected := 0
+	for v, p := range h.PopWhile(func(_, p int) bool { return p < due }) {
+		if v != expected || p != expected {
+			t.Fatalf("expected (%d, %d), got (%d, %d)", expected, expected, v, p)
+		}
+		expected++
+	}
+	if expected != due {
+		t.Fatalf("expected %d values popped, got %d", due, expected)
+	}
+	if v, ok := h.TryPeek(); !ok || v != due {
+		t.Fatalf("expected %d to be left in the heap, got (%d, %t)", due, v, ok)
+	}
+	for range h.PopWhile(func(int, int) bool { return true }) {
+		break
+	}
+	if n := h.Len(); n != N-due-1 {
+		t.Fatalf("expected %d values left after breaking, got %d", N-due-1, n)
+	}
+}
+
+func TestFHeapAll_Deterministic(t *testing.T) {
+	N := *HeapSize
+	dump := func() []Item[int, int] {
+		h := New[int, int](func(x, y int) bool { return x < y }, -1,
+			WithDeterministicDump[int, int]())
+		for i := range N {
+			if err := h.Push(i, i%10); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var items []Item[int, int]
+		for v, p := range h.All() {
+			items = append(items, Item[int, int]{v, p})
+		}
+		return items
+	}
+	first := dump()
+	if len(first) != N {
+		t.Fatalf("expected %d values, got %d", N, len(first))
+	}
+	for i := 1; i < N; i++ {
+		x, y := first[i-1], first[i]
+		if x.Priority > y.Priority || x.Priority == y.Priority && x.Value > y.Value {
+			t.Fatalf("expected %v to follow %v", y, x)
+		}
+	}
+	for range 3 {
+		if again := dump(); !slices.Equal(first, again) {
+			t.Fatal("expected consecutive dumps to be equal")
+		}
+	}
+}
+
+func TestFHeapPopWhile_TTL(t *testing.T) {
+	var expired []int
+	h := New(func(x, y int) bool { return x < y }, math.MinInt,
+		WithTTL(time.Minute, func(value int, _ int) { expired = append(expired, value) }))
+	now := time.Unix(0, 0)
+	h.expiry.now = func() time.Time { return now }
+	if err := h.Push(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(30 * time.Second)
+	for v := 1; v <= 3; v++ {
+		if err := h.Push(v, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 0 expires, and mustn't be what the predicate is tested on
+	now = now.Add(45 * time.Second)
+	var popped []int
+	for v := range h.PopWhile(func(_, p int) bool { return p < 2 }) {
+		popped = append(popped, v)
+	}
+	if !slices.Equal(popped, []int{1}) || !slices.Equal(expired, []int{0}) {
+		t.Fatalf("expected to pop [1] and expire [0], got %v and %v", popped, expired)
+	}
+	if v, ok := h.TryPeek(); !ok || v != 2 {
+		t.Fatalf("expected 2 to be left in the heap, got (%d, %t)", v, ok)
+	}
+}
@@ -0,0 +1,131 @@
+package fheap
+
+import "fmt"
+
+// KeyedHeap is a Fibonacci heap whose values needn't be comparable. Values
+// are identified by a comparable key computed from them by a callback, so
+// values with equal keys are duplicates.
+type KeyedHeap[V any, K comparable, P any] struct {
+	inner    *Heap[K, P]
+	key      func(value V) K
+	payloads map[K]V
+}
+
+// NewWithKey creates an empty KeyedHeap identifying values by `key`.
+// Its other arguments are as for New, with options applying to keys.
+// NewWithKey panics with ErrNilComparator if `higherThan` is nil, and with
+// an error wrapping ErrMisconfigured if the options allow duplicate keys or
+// leave keys unindexed, since values are looked up by key.
+func NewWithKey[V any, K comparable, P any](key func(value V) K, higherThan func(x, y P) bool, highestPriority P, opts ...Option[K, P]) *KeyedHeap[V, K, P] {
+	inner := New(higherThan, highestPriority, opts...)
+	if inner.duplicates == DuplicatesAllow {
+		panic(fmt.Errorf("%w: keyed heaps can't allow duplicate keys", ErrMisconfigured))
+	}
+	if inner.unindexed {
+		panic(fmt.Errorf("%w: keyed heaps need their keys indexed", ErrMisconfigured))
+	}
+	kh := &KeyedHeap[V, K, P]{
+		inner:    inner,
+		key:      key,
+		payloads: map[K]V{},
+	}
+	// keys the heap discards by itself take their payloads with them
+	if e := inner.expiry; e != nil {
+		e.onExpire = kh.dropping(e.onExpire)
+	}
+	if m := inner.memory; m != nil {
+		m.onEvict = kh.dropping(m.onEvict)
+	}
+	if c := inner.capacity; c != nil {
+		c.onEvict = kh.dropping(c.onEvict)
+	}
+	return kh
+}
+
+// dropping returns a callback deleting a key's payload before reporting the
+// key to `report`, if it isn't nil.
+func (kh *KeyedHeap[V, K, P]) dropping(report func(key K, priority P)) func(key K, priority P) {
+	return func(key K, priority P) {
+		delete(kh.payloads, key)
+		if report != nil {
+			report(key, priority)
+		}
+	}
+}
+
+// Size returns the number of elements in the heap.
+func (kh *KeyedHeap[V, K, P]) Size() (int, error) {
+	if kh == nil {
+		return 0, ErrNilHeap
+	}
+	return kh.inner.Size()
+}
+
+// Push inserts a given value with the supplied priority into the heap.
+// Values whose key is already in the heap are handled according to the
+// heap's DuplicatePolicy, with the pushed value replacing the held one
+// unless the push is rejected.
+func (kh *KeyedHeap[V, K, P]) Push(value V, priority P) error {
+	if kh == nil {
+		return ErrNilHeap
+	}
+	key := kh.key(value)
+	if err := kh.inner.Push(key, priority); err != nil {
+		return err
+	}
+	if _, ok := kh.inner.values[key]; ok {
+		// the key wasn't evicted by its own push
+		kh.payloads[key] = value
+	}
+	return nil
+}
+
+// Pop removes and returns the highest-priority value from the heap.
+func (kh *KeyedHeap[V, K, P]) Pop() (value V, err error) {
+	if kh == nil {
+		return value, ErrNilHeap
+	}
+	key, err := kh.inner.Pop()
+	if err != nil {
+		return value, err
+	}
+	value = kh.payloads[key]
+	delete(kh.payloads, key)
+	return value, nil
+}
+
+// TryPeek returns the highest-priority value in the heap without removing
+// it, reporting whether there was one.
+func (kh *KeyedHeap[V, K, P]) TryPeek() (value V, ok bool) {
+	if kh == nil {
+		return
+	}
+	key, ok := kh.inner.TryPeek()
+	if !ok {
+		return
+	}
+	return kh.payloads[key], true
+}
+
+// IncreasePriority increases the priority of the value in the heap with
+// the same key as the given value, if present.
+func (kh *KeyedHeap[V, K, P]) IncreasePriority(value V, priority P) error {
+	if kh == nil {
+		return ErrNilHeap
+	}
+	return kh.inner.IncreasePriority(kh.key(value), priority)
+}
+
+// Delete deletes the value in the heap with the same key as the given
+// value, if present.
+func (kh *KeyedHeap[V, K, P]) Delete(value V) error {
+	if kh == nil {
+		return ErrNilHeap
+	}
+	key := kh.key(value)
+	if err := kh.inner.Delete(key); err != nil {
+		return err
+	}
+	delete(kh.payloads, key)
+	return nil
+}
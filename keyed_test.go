@@ -0,0 +1,119 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type keyedJob struct {
+	name string
+	args []string
+}
+
+func TestKeyedHeap(t *testing.T) {
+	kh := NewWithKey(func(j keyedJob) string { return j.name },
+		func(x, y int) bool { return x < y }, math.MinInt)
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		job := keyedJob{name: strconv.Itoa(p), args: []string{strconv.Itoa(p)}}
+		if err := kh.Push(job, p+N); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := kh.Push(keyedJob{name: "0"}, 0); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	for p := 0; p < N; p += 2 {
+		if err := kh.IncreasePriority(keyedJob{name: strconv.Itoa(p)}, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for p := 1; p < N; p += 2 {
+		if err := kh.Delete(keyedJob{name: strconv.Itoa(p)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := isFibonacciHeap(kh.inner); err != nil {
+		t.Fatal(err)
+	}
+	for p := 0; p < N; p += 2 {
+		if job, ok := kh.TryPeek(); !ok || job.name != strconv.Itoa(p) {
+			t.Fatalf("[TryPeek] expected job %d, got %v", p, job)
+		}
+		job, err := kh.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.name != strconv.Itoa(p) || len(job.args) != 1 || job.args[0] != job.name {
+			t.Fatalf("expected job %d, got %v", p, job)
+		}
+	}
+	if len(kh.payloads) != 0 {
+		t.Fatalf("expected no payloads left, got %d", len(kh.payloads))
+	}
+}
+
+func TestKeyedHeap_Eviction(t *testing.T) {
+	key := func(j keyedJob) string { return j.name }
+	less := func(x, y int) bool { return x < y }
+	t.Run("Overflow", func(t *testing.T) {
+		kh := NewWithKey(key, less, math.MinInt,
+			WithMaxSize[string, int](2, OverflowEvictWorst))
+		for p, name := range []string{"a", "b", "c", "d"} {
+			if err := kh.Push(keyedJob{name: name}, 3-p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// "a" is the lowest-priority value, so its own push drops it
+		if err := kh.Push(keyedJob{name: "a"}, 9); err != nil {
+			t.Fatal(err)
+		}
+		if len(kh.payloads) != 2 {
+			t.Fatalf("expected 2 payloads, got %v", kh.payloads)
+		}
+		for _, name := range []string{"d", "c"} {
+			if _, ok := kh.payloads[name]; !ok {
+				t.Fatalf("expected a payload for %s, got %v", name, kh.payloads)
+			}
+		}
+	})
+	t.Run("TTL", func(t *testing.T) {
+		var expired []string
+		kh := NewWithKey(key, less, math.MinInt,
+			WithTTL(time.Minute, func(name string, _ int) { expired = append(expired, name) }))
+		now := time.Unix(0, 0)
+		kh.inner.expiry.now = func() time.Time { return now }
+		for p, name := range []string{"a", "b"} {
+			if err := kh.Push(keyedJob{name: name}, p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		now = now.Add(time.Hour)
+		if _, err := kh.Pop(); err != ErrEmptyHeap {
+			t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+		}
+		if len(expired) != 2 {
+			t.Fatalf("expected 2 expired keys, got %v", expired)
+		}
+		if len(kh.payloads) != 0 {
+			t.Fatalf("expected no payloads left, got %v", kh.payloads)
+		}
+	})
+}
+
+func TestNewWithKey_Misconfigured(t *testing.T) {
+	key := func(j keyedJob) string { return j.name }
+	less := func(x, y int) bool { return x < y }
+	for name, opt := range map[string]Option[string, int]{
+		"AllowDuplicates": WithAllowDuplicates[string, int](),
+		"WithoutIndex":    WithoutIndex[string, int](),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mustPanic(t, ErrMisconfigured, func() { NewWithKey(key, less, math.MinInt, opt) })
+		})
+	}
+}
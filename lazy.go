@@ -0,0 +1,92 @@
+package fheap
+
+// LazyHeap is a Fibonacci heap whose priorities are computed from values by
+// a callback. Priorities are computed once when a value is pushed, and
+// cached until the value is invalidated, which re-positions it in the heap.
+type LazyHeap[V comparable, P any] struct {
+	inner    *Heap[V, P]
+	priority func(value V) P
+}
+
+// NewLazy creates an empty LazyHeap computing priorities with `priority`.
+// Its other arguments are as for New.
+func NewLazy[V comparable, P any](priority func(value V) P, higherThan func(x, y P) bool, highestPriority P, opts ...Option[V, P]) *LazyHeap[V, P] {
+	return &LazyHeap[V, P]{inner: New(higherThan, highestPriority, opts...), priority: priority}
+}
+
+// Size returns the number of elements in the heap.
+func (lh *LazyHeap[V, P]) Size() (int, error) {
+	if lh == nil {
+		return 0, ErrNilHeap
+	}
+	return lh.inner.Size()
+}
+
+// Push inserts a value into the heap with its computed priority.
+func (lh *LazyHeap[V, P]) Push(value V) error {
+	if lh == nil {
+		return ErrNilHeap
+	}
+	return lh.inner.Push(value, lh.priority(value))
+}
+
+// Pop removes and returns the highest-priority value from the heap.
+func (lh *LazyHeap[V, P]) Pop() (V, error) {
+	if lh == nil {
+		var value V
+		return value, ErrNilHeap
+	}
+	return lh.inner.Pop()
+}
+
+// TryPeek returns the highest-priority value in the heap without removing
+// it, reporting whether there was one.
+func (lh *LazyHeap[V, P]) TryPeek() (value V, ok bool) {
+	if lh == nil {
+		return
+	}
+	return lh.inner.TryPeek()
+}
+
+// Priority returns a value's cached priority.
+func (lh *LazyHeap[V, P]) Priority(value V) (priority P, err error) {
+	if lh == nil {
+		return priority, ErrNilHeap
+	}
+	if lh.inner.unindexed {
+		return priority, errUnindexed
+	}
+	x, ok := lh.inner.values[value]
+	if !ok {
+		return priority, &OpError[V, P]{Op: "Priority", Value: value, Err: ErrValueNotFound}
+	}
+	return x.priority, nil
+}
+
+// Invalidate recomputes a value's priority and re-positions the value in
+// the heap accordingly.
+func (lh *LazyHeap[V, P]) Invalidate(value V) (err error) {
+	if lh == nil {
+		return ErrNilHeap
+	}
+	if lh.inner.corrupted != nil {
+		return lh.inner.corrupted
+	}
+	defer lh.inner.recoverPanic(&err)
+	if lh.inner.prioritaire == nil {
+		return ErrEmptyHeap
+	}
+	priority := lh.priority(value)
+	if err := lh.inner.checkPriority(priority); err != nil {
+		return err
+	}
+	return lh.inner.setPriority(value, priority)
+}
+
+// Delete deletes a value from the heap, if present.
+func (lh *LazyHeap[V, P]) Delete(value V) error {
+	if lh == nil {
+		return ErrNilHeap
+	}
+	return lh.inner.Delete(value)
+}
@@ -0,0 +1,47 @@
+package fheap
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLazyHeap(t *testing.T) {
+	load := map[string]int{"a": 5, "b": 3, "c": 8, "d": 1, "e": 6}
+	h := NewLazy[string, int](func(v string) int { return load[v] },
+		func(x, y int) bool { return x < y }, math.MinInt)
+	for v := range load {
+		if err := h.Push(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	} else if v != "d" {
+		t.Fatalf("expected value=d, got %s", v)
+	}
+	load["b"] = 10
+	load["c"] = 2
+	if p, err := h.Priority("b"); err != nil {
+		t.Fatal(err)
+	} else if p != 3 {
+		t.Fatalf("expected cached priority=3, got %d", p)
+	}
+	for _, v := range []string{"b", "c"} {
+		if err := h.Invalidate(v); err != nil {
+			t.Fatal(err)
+		}
+		if err := isFibonacciHeap(h.inner); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, expected := range []string{"c", "a", "e", "b"} {
+		if v, err := h.Pop(); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected value=%s, got %s", expected, v)
+		}
+	}
+	if err := h.Invalidate("a"); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+}
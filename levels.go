@@ -0,0 +1,66 @@
+package fheap
+
+import (
+	"errors"
+	"strconv"
+)
+
+var ErrNegativeLevel = errors.New("negative level")
+
+// Level is a discrete priority level, where lower levels are higher
+// priorities. Valid levels are non-negative, the zero Level being reserved
+// as the sentinel highest priority, so that a heap's values have levels of
+// at least Critical.
+type Level int
+
+// Common priority levels, from highest to lowest.
+const (
+	Critical Level = iota + 1
+	High
+	Normal
+	Low
+)
+
+// levelReserved is the sentinel highest-priority Level.
+const levelReserved Level = 0
+
+// String returns the level's name.
+func (l Level) String() string {
+	switch l {
+	case levelReserved:
+		return "Reserved"
+	case Critical:
+		return "Critical"
+	case High:
+		return "High"
+	case Normal:
+		return "Normal"
+	case Low:
+		return "Low"
+	}
+	return "Level(" + strconv.Itoa(int(l)) + ")"
+}
+
+// HigherLevel determines if the first level is higher than the second.
+func HigherLevel(x, y Level) bool {
+	return x < y
+}
+
+// NewLeveled creates an empty Fibonacci heap with Level priorities.
+// Negative levels, which HigherLevel would rank above the sentinel, are
+// rejected with ErrNegativeLevel, before any validator given with
+// WithPriorityValidator is consulted.
+func NewLeveled[V comparable](opts ...Option[V, Level]) *Heap[V, Level] {
+	fh := New(HigherLevel, levelReserved, opts...)
+	validate := fh.validate
+	fh.validate = func(level Level) error {
+		if level < levelReserved {
+			return ErrNegativeLevel
+		}
+		if validate != nil {
+			return validate(level)
+		}
+		return nil
+	}
+	return fh
+}
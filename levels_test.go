@@ -0,0 +1,66 @@
+package fheap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLevelString(t *testing.T) {
+	for level, expected := range map[Level]string{
+		0:        "Reserved",
+		Critical: "Critical",
+		High:     "High",
+		Normal:   "Normal",
+		Low:      "Low",
+		Low + 1:  "Level(5)",
+	} {
+		if actual := level.String(); actual != expected {
+			t.Fatalf("expected %q, got %q", expected, actual)
+		}
+	}
+}
+
+func TestNewLeveled(t *testing.T) {
+	h := NewLeveled[string]()
+	var unset Level
+	if err := h.Push("unset", unset); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := h.Push("negative", -1); err != ErrNegativeLevel {
+		t.Fatalf("expected %v, got %v", ErrNegativeLevel, err)
+	}
+	for _, level := range []Level{Low, Normal, Critical, High} {
+		if err := Push(h, level.String(), level, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Delete(h, "Normal", t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"Critical", "High", "Low"} {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected value=%s, got %s", expected, v)
+		}
+	}
+}
+
+func TestNewLeveled_Validator(t *testing.T) {
+	errNotLow := errors.New("not low")
+	h := NewLeveled(WithPriorityValidator[string](func(level Level) error {
+		if level != Low {
+			return errNotLow
+		}
+		return nil
+	}))
+	if err := h.Push("negative", -1); err != ErrNegativeLevel {
+		t.Fatalf("expected %v, got %v", ErrNegativeLevel, err)
+	}
+	if err := h.Push("high", High); err != errNotLow {
+		t.Fatalf("expected %v, got %v", errNotLow, err)
+	}
+	if err := Push(h, "low", Low, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+}
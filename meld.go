@@ -0,0 +1,232 @@
+package fheap
+
+// Meld moves every element of `other` into the heap, leaving `other` empty.
+// The root lists are spliced together without consolidating, however each
+// of `other`'s values is checked against the heap's, so Meld is linear in
+// the size of `other`. Both heaps must order priorities the same way.
+// Handles to `other`'s values are then to be used with the heap.
+// No element is moved if `other` holds a value already in the heap, or a
+// priority reserved or rejected by the heap. ErrSelfMeld is returned if
+// `other` is the heap itself.
+func (fh *Heap[V, P]) Meld(other *Heap[V, P]) error {
+	return fh.MeldAll(other)
+}
+
+// MeldAll moves every element of the given heaps into the heap, leaving
+// them empty, as by Meld. No element is moved if a value is held by more
+// than one heap, a priority reserved or rejected by the heap is held by
+// any, or the heap rejects overflowing pushes and can't hold them all.
+// ErrSelfMeld is returned if the heap is among the given heaps, and
+// ErrDuplicateHeap if a heap is given more than once.
+func (fh *Heap[V, P]) MeldAll(others ...*Heap[V, P]) (err error) {
+	if debug {
+		defer fh.assertInvariants()
+		for _, other := range others {
+			defer other.assertInvariants()
+		}
+	}
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	incoming := 0
+	listed := make(map[*Heap[V, P]]bool, len(others))
+	for _, other := range others {
+		if other == nil {
+			return ErrNilHeap
+		}
+		if other == fh {
+			return ErrSelfMeld
+		}
+		if listed[other] {
+			return ErrDuplicateHeap
+		}
+		listed[other] = true
+		if other.corrupted != nil {
+			return other.corrupted
+		}
+		incoming += other.len()
+	}
+	if incoming == 0 {
+		return nil
+	}
+	defer fh.recoverPanic(&err)
+	if err := fh.lazyInit(); err != nil {
+		return err
+	}
+	seen := make(map[V]bool, incoming)
+	for _, other := range others {
+		for node := range other.nodes() {
+			value := node.value
+			if fh.collides(value) || seen[value] && !fh.allowsDuplicates() {
+				return &OpError[V, P]{"Meld", value, node.priority, ErrDuplicateValue}
+			}
+			if err := fh.checkPriority(node.priority); err != nil {
+				return err
+			}
+			seen[value] = true
+		}
+	}
+	if err := fh.admit(incoming); err != nil {
+		return err
+	}
+	if incoming > fh.len() {
+		if err := fh.Reserve(incoming); err != nil {
+			return err
+		}
+	}
+	for _, other := range others {
+		if other.prioritaire == nil {
+			continue
+		}
+		if err := fh.meld(other); err != nil {
+			return err
+		}
+	}
+	return fh.trim()
+}
+
+// meld splices a non-empty heap's root list into the heap's, and moves its
+// values into the heap's map.
+func (fh *Heap[V, P]) meld(other *Heap[V, P]) error {
+	fh.diagnostics.record("Meld", other.prioritaire.value, other.prioritaire.priority)
+	for node := range other.nodes() {
+		node.owner = fh
+		fh.index(node.value, node)
+		fh.changes.record(node.value, false)
+		other.changes.record(node.value, true)
+	}
+	fh.marked += other.marked
+	fh.maxDegree = max(fh.maxDegree, other.maxDegree)
+	fh.seq = max(fh.seq, other.seq)
+	fh.observeSize()
+	ring := other.prioritaire
+	if other.relaxation != nil {
+		// a relaxed heap's prioritaire may not be its highest-priority root
+		for root := ring.right; root != other.prioritaire; root = root.right {
+			if fh.nodeHigherThan(root, ring) {
+				ring = root
+			}
+		}
+	}
+	fh.extendBound(ring.priority)
+	other.prioritaire = nil
+	other.newIndex(0)
+	other.peak = 0
+	other.marked = 0
+	other.maxDegree = 0
+	other.watermarks.observe(0)
+	if fh.prioritaire == nil {
+		fh.prioritaire = ring
+		return nil
+	}
+	if err := fh.prioritaire.splice(ring); err != nil {
+		return err
+	}
+	if fh.nodeHigherThan(ring, fh.prioritaire) {
+		fh.prioritaire = ring
+	}
+	return nil
+}
+
+// Split moves every element for which `pred` holds into a new heap with the
+// same configuration, which is returned. Matching nodes are cut from the
+// heap directly, and the heap is consolidated once, whereas the new heap's
+// root list is left unconsolidated. Handles to moved values remain valid,
+// to be used with the new heap.
+func (fh *Heap[V, P]) Split(pred func(value V, priority P) bool) (split *Heap[V, P], err error) {
+	if debug {
+		defer fh.assertInvariants()
+		defer func() { split.assertInvariants() }()
+	}
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return nil, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	var matches []*fnode[V, P]
+	for x := range fh.nodes() {
+		var match bool
+		fh.callback(func() { match = pred(x.value, x.priority) })
+		if match {
+			matches = append(matches, x)
+		}
+	}
+	if err := fh.extract(matches); err != nil {
+		return nil, err
+	}
+	split = fh.emptyCopy()
+	for _, x := range matches {
+		x.left, x.right = x, x
+		x.owner = split
+		split.index(x.value, x)
+		split.changes.record(x.value, false)
+		if split.prioritaire == nil {
+			split.prioritaire = x
+			continue
+		}
+		if err := split.prioritaire.insertLeft(x); err != nil {
+			return nil, err
+		}
+		if split.nodeHigherThan(x, split.prioritaire) {
+			split.prioritaire = x
+		}
+	}
+	if split.relaxation != nil && split.prioritaire != nil {
+		split.relaxation.bound = split.prioritaire.priority
+	}
+	split.observeSize()
+	return split, nil
+}
+
+// emptyCopy returns an empty heap with the same configuration as the heap.
+func (fh *Heap[V, P]) emptyCopy() *Heap[V, P] {
+	h := &Heap[V, P]{
+		higherThan:      fh.comparator(),
+		unchecked:       fh.unchecked,
+		checkEvery:      fh.checkEvery,
+		highestPriority: fh.highestPriority,
+		reserved:        fh.reserved,
+		duplicates:      fh.duplicates,
+		intern:          fh.intern,
+		codec:           fh.codec,
+		copyValue:       fh.copyValue,
+		copyPriority:    fh.copyPriority,
+		timestamps:      fh.timestamps,
+		fifo:            fh.fifo,
+		equal:           fh.equal,
+		validate:        fh.validate,
+		capacity:        fh.capacity,
+		expiry:          fh.expiry,
+		seq:             fh.seq,
+		deterministic:   fh.deterministic,
+		probed:          fh.probed,
+		pool:            fh.pool,
+		unindexed:       fh.unindexed,
+		eagerLinks:      fh.eagerLinks,
+	}
+	h.newIndex(0)
+	if fh.diagnostics != nil {
+		h.diagnostics = &diagnostics[V, P]{report: fh.diagnostics.report}
+	}
+	if fh.watermarks != nil {
+		h.watermarks = &watermarks{low: fh.watermarks.low, high: fh.watermarks.high,
+			onHigh: fh.watermarks.onHigh, onLow: fh.watermarks.onLow}
+	}
+	if fh.relaxation != nil {
+		h.relaxation = &relaxation[P]{within: fh.relaxation.within}
+	}
+	if fh.changes != nil {
+		h.changes = &changeTracker[V]{changes: map[V]change{}}
+	}
+	if fh.memory != nil {
+		m := *fh.memory
+		m.pushes = 0
+		h.memory = &m
+	}
+	return h
+}
@@ -0,0 +1,199 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapMeld(t *testing.T) {
+	h, other := intMinHeap[int](), intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		dst := h
+		if p%3 == 0 {
+			dst = other
+		}
+		if err := Push(dst, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// give other some structure
+	if _, err := Pop(other, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Meld(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(other); err != nil {
+		t.Fatal(err)
+	}
+	if n := other.Len(); n != 0 {
+		t.Fatalf("expected other to be empty, got size=%d", n)
+	}
+	if n := h.Len(); n != N-1 {
+		t.Fatalf("expected size=%d, got %d", N-1, n)
+	}
+	for expected := 1; expected < N; expected++ {
+		v, err := Pop(h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
+
+func TestFHeapMeldRejected(t *testing.T) {
+	h, other := intMinHeap[string](), intMinHeap[string]()
+	for i, v := range []string{"a", "b", "c"} {
+		if err := h.Push(v, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := other.Push("d", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Push("b", 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Meld(other); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if h.Len() != 3 || other.Len() != 2 {
+		t.Fatalf("expected sizes (3, 2), got (%d, %d)", h.Len(), other.Len())
+	}
+	unreserved := NewUnreserved[string, int](func(x, y int) bool { return x < y })
+	if err := unreserved.Push("e", math.MinInt); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Meld(unreserved); !errors.Is(err, ErrReservedPriority) {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := h.Meld(nil); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+	for _, self := range []*Heap[string, int]{
+		h,
+		New(func(x, y int) bool { return x < y }, math.MinInt, WithAllowDuplicates[string, int]()),
+		New(func(x, y int) bool { return x < y }, math.MinInt, WithoutIndex[string, int]()),
+	} {
+		if err := self.Push("f", 5); err != nil {
+			t.Fatal(err)
+		}
+		size := self.Len()
+		if err := self.Meld(self); err != ErrSelfMeld {
+			t.Fatalf("expected %v, got %v", ErrSelfMeld, err)
+		}
+		if self.Len() != size {
+			t.Fatalf("expected size=%d, got %d", size, self.Len())
+		}
+		if err := self.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Meld(intMinHeap[string]()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFHeapSplit(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	handle, err := h.PushHandle(-2, -2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	split, err := h.Split(func(v, _ int) bool { return v%2 == 0 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(split); err != nil {
+		t.Fatal(err)
+	}
+	if h.Len()+split.Len() != N+1 || split.Len() != N/2+1 {
+		t.Fatalf("expected sizes (%d, %d), got (%d, %d)", N-N/2, N/2+1, h.Len(), split.Len())
+	}
+	if err := split.IncreasePriorityHandle(handle, -3); err != nil {
+		t.Fatal(err)
+	}
+	for _, heap := range []*Heap[int, int]{h, split} {
+		prev := math.MinInt
+		for heap.Len() > 0 {
+			v, p, err := heap.PopPair()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (v%2 == 0) != (heap == split) {
+				t.Fatalf("value %d in the wrong heap", v)
+			}
+			if p < prev {
+				t.Fatalf("popped priority %d after %d", p, prev)
+			}
+			prev = p
+		}
+	}
+}
+
+func TestFHeapMeldAll(t *testing.T) {
+	N := *HeapSize
+	const k = 4
+	h := intMinHeap[int]()
+	others := make([]*Heap[int, int], k)
+	for i := range others {
+		others[i] = intMinHeap[int]()
+	}
+	for _, p := range rand.Perm(N) {
+		if err := Push(others[p%k], p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clash := intMinHeap[int]()
+	if err := clash.Push(N-1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.MeldAll(append(others, clash)...); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if h.Len() != 0 {
+		t.Fatalf("expected no values to be moved, got %d", h.Len())
+	}
+	if err := h.MeldAll(others[0], nil); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+	if err := h.MeldAll(others[0], others[1], others[0]); err != ErrDuplicateHeap {
+		t.Fatalf("expected %v, got %v", ErrDuplicateHeap, err)
+	}
+	if err := h.MeldAll(others[0], h); err != ErrSelfMeld {
+		t.Fatalf("expected %v, got %v", ErrSelfMeld, err)
+	}
+	if h.Len() != 0 || others[0].Len() == 0 {
+		t.Fatalf("expected no values to be moved, got sizes (%d, %d)", h.Len(), others[0].Len())
+	}
+	if err := h.MeldAll(others...); err != nil {
+		t.Fatal(err)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	for _, other := range others {
+		if other.Len() != 0 {
+			t.Fatalf("expected melded heaps to be empty, got size=%d", other.Len())
+		}
+	}
+	for expected := 0; expected < N; expected++ {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
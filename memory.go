@@ -0,0 +1,177 @@
+package fheap
+
+import (
+	"maps"
+	rtdebug "runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"unsafe"
+)
+
+// memoryCheckInterval is the number of pushes between memory usage checks.
+const memoryCheckInterval = 1024
+
+// WithMemoryEviction makes the heap shed load as the process approaches a
+// soft memory limit. Every so many pushes, the process's memory usage is
+// compared against `soft`, or the runtime's memory limit if `soft` is 0.
+// If usage exceeds it, enough of the lowest-priority entries are evicted to
+// bring usage down to `target` by an estimate of each entry's footprint.
+// Evicted entries are reported to `onEvict`.
+func WithMemoryEviction[V comparable, P any](soft, target uint64, onEvict func(value V, priority P)) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.memory = &memoryPolicy[V, P]{
+			soft:      soft,
+			target:    target,
+			onEvict:   onEvict,
+			readUsage: readMemoryUsage,
+		}
+	}
+}
+
+// memoryPolicy is a heap's memory-pressure eviction configuration.
+type memoryPolicy[V, P any] struct {
+	soft, target uint64
+	onEvict      func(value V, priority P)
+	readUsage    func() uint64
+	pushes       int
+}
+
+// readMemoryUsage returns the process's memory usage as accounted for by
+// the runtime's memory limit.
+func readMemoryUsage() uint64 {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	return samples[0].Value.Uint64() - samples[1].Value.Uint64()
+}
+
+// entryFootprint estimates the memory used by a heap entry: its node, and
+// its slot in the values map.
+func entryFootprint[V comparable, P any]() uint64 {
+	var value V
+	var node *fnode[V, P]
+	return uint64(unsafe.Sizeof(*node) + unsafe.Sizeof(value) + unsafe.Sizeof(node))
+}
+
+// relieveMemoryPressure evicts the lowest-priority entries if the process's
+// memory usage exceeds the soft limit. Relieving is a no-op unless memory
+// eviction is enabled, and is only done every memoryCheckInterval pushes.
+func (fh *Heap[V, P]) relieveMemoryPressure() error {
+	m := fh.memory
+	if m == nil {
+		return nil
+	}
+	if m.pushes++; m.pushes < memoryCheckInterval {
+		return nil
+	}
+	m.pushes = 0
+	soft := m.soft
+	if soft == 0 {
+		soft = uint64(rtdebug.SetMemoryLimit(-1))
+	}
+	usage := m.readUsage()
+	if usage <= soft || usage <= m.target {
+		return nil
+	}
+	footprint := entryFootprint[V, P]()
+	n := (usage - m.target + footprint - 1) / footprint
+	_, err := fh.EvictLowest(int(min(n, uint64(fh.len()))), m.onEvict)
+	return err
+}
+
+// EvictLowest deletes the n lowest-priority entries from the heap,
+// reporting each to onEvict if it isn't nil, and returns how many entries
+// were evicted. The entries are extracted at once, so that the heap is
+// consolidated once.
+func (fh *Heap[V, P]) EvictLowest(n int, onEvict func(value V, priority P)) (evicted int, err error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return 0, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return 0, fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	lowest, err := fh.lowest(n)
+	if err != nil {
+		return 0, err
+	}
+	if err := fh.extract(lowest); err != nil {
+		return 0, err
+	}
+	if onEvict != nil {
+		for _, x := range lowest {
+			fh.callback(func() { onEvict(x.value, x.priority) })
+		}
+	}
+	return len(lowest), nil
+}
+
+// lowest returns the heap's n lowest-priority nodes, lowest first. It scans
+// the heap's nodes while maintaining a heap of the n lowest nodes seen so
+// far, whose top is the highest of them. Since values may be unindexed,
+// kept nodes are keyed by their position among the candidates.
+func (fh *Heap[V, P]) lowest(n int) ([]*fnode[V, P], error) {
+	n = min(n, fh.len())
+	if n <= 0 {
+		return nil, nil
+	}
+	kept := NewUnreserved[int](fh.comparator())
+	var candidates []*fnode[V, P]
+	for node := range fh.nodes() {
+		if kept.len() == n {
+			if !fh.higherThan(kept.prioritaire.priority, node.priority) {
+				continue
+			}
+			if _, err := kept.Pop(); err != nil {
+				return nil, err
+			}
+		}
+		if err := kept.Push(len(candidates), node.priority); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, node)
+	}
+	lowest := make([]*fnode[V, P], n)
+	for i := n - 1; i >= 0; i-- {
+		j, err := kept.Pop()
+		if err != nil {
+			return nil, err
+		}
+		lowest[i] = candidates[j]
+	}
+	return lowest, nil
+}
+
+// Compact rebuilds the heap's value index at the heap's current size, and
+// releases its scratch table and any pooled nodes, so that memory held
+// since the heap was larger can be reclaimed. It returns an estimate of the
+// bytes freed, excluding pooled nodes.
+func (fh *Heap[V, P]) Compact() (uint64, error) {
+	if debug {
+		defer fh.assertInvariants()
+	}
+	if fh == nil {
+		return 0, ErrNilHeap
+	}
+	var value V
+	var node *fnode[V, P]
+	freed := uint64(fh.peak-fh.len()) * uint64(unsafe.Sizeof(value)+unsafe.Sizeof(node))
+	freed += uint64(cap(fh.scratch)) * uint64(unsafe.Sizeof(node))
+	if !fh.unindexed {
+		// maps.Clone would keep the index's size
+		compacted := make(map[V]*fnode[V, P], fh.len())
+		maps.Copy(compacted, fh.values)
+		fh.values = compacted
+	}
+	fh.peak = fh.len()
+	fh.scratch = nil
+	if fh.pool != nil {
+		fh.pool = new(sync.Pool)
+	}
+	return freed, nil
+}
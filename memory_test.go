@@ -0,0 +1,110 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"unsafe"
+)
+
+func TestFHeapEvictLowest(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var evicted []int
+	n, err := h.EvictLowest(N/4, func(v, p int) { evicted = append(evicted, p) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != N/4 || len(evicted) != N/4 {
+		t.Fatalf("expected %d evictions, got %d", N/4, n)
+	}
+	for i, p := range evicted {
+		if p != N-1-i {
+			t.Fatalf("expected evicted priority=%d, got %d", N-1-i, p)
+		}
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	if size, _ := h.Size(); size != N-N/4 {
+		t.Fatalf("expected size=%d, got %d", N-N/4, size)
+	}
+	unreserved := NewUnreserved[int, int](func(x, y int) bool { return x < y })
+	if err := unreserved.Push(0, math.MaxInt); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := unreserved.EvictLowest(1, nil); err != nil || n != 1 {
+		t.Fatalf("expected to evict 1 value, got (%d, %v)", n, err)
+	}
+}
+
+func TestFHeapMemoryEviction(t *testing.T) {
+	evicted := 0
+	h := New[int, int](func(x, y int) bool { return x < y }, math.MinInt,
+		WithMemoryEviction[int, int](1000, 500, func(v, p int) { evicted++ }))
+	footprint := entryFootprint[int, int]()
+	usage := uint64(0)
+	h.memory.readUsage = func() uint64 { return usage }
+	for i := 0; i < memoryCheckInterval; i++ {
+		if err := h.Push(i, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if evicted != 0 {
+		t.Fatalf("expected no evictions under the soft limit, got %d", evicted)
+	}
+	usage = 500 + 10*footprint
+	for i := memoryCheckInterval; i < 2*memoryCheckInterval; i++ {
+		if err := h.Push(i, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if evicted != 10 {
+		t.Fatalf("expected 10 evictions, got %d", evicted)
+	}
+	if _, ok := h.values[2*memoryCheckInterval-2]; ok {
+		t.Fatal("expected the lowest-priority values to be evicted")
+	}
+}
+
+func TestFHeapCompact(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range N - N/10 {
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	freed, err := h.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed < uint64(N-N/10)*2*uint64(unsafe.Sizeof(0)) {
+		t.Fatalf("expected at least %d index slots to be freed, got %d bytes", N-N/10, freed)
+	}
+	if freed, err := h.Compact(); err != nil {
+		t.Fatal(err)
+	} else if freed != 0 {
+		t.Fatalf("expected nothing more to be freed, got %d bytes", freed)
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	for expected := N - N/10; expected < N; expected++ {
+		if v, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != expected {
+			t.Fatalf("expected %d, got %d", expected, v)
+		}
+	}
+}
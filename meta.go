@@ -0,0 +1,35 @@
+package fheap
+
+// SetMeta attaches arbitrary metadata, such as a retry count or trace ID,
+// to a value in the heap, replacing any previously attached. Metadata is
+// kept when the value's priority changes, and discarded with the value.
+func (fh *Heap[V, P]) SetMeta(value V, meta any) error {
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.unindexed {
+		return errUnindexed
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return &OpError[V, P]{Op: "SetMeta", Value: value, Err: ErrValueNotFound}
+	}
+	x.extra().meta = meta
+	return nil
+}
+
+// GetMeta returns the metadata attached to a value in the heap, which is
+// nil if none was attached.
+func (fh *Heap[V, P]) GetMeta(value V) (any, error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.unindexed {
+		return nil, errUnindexed
+	}
+	x, ok := fh.values[value]
+	if !ok {
+		return nil, &OpError[V, P]{Op: "GetMeta", Value: value, Err: ErrValueNotFound}
+	}
+	return x.meta(), nil
+}
@@ -0,0 +1,53 @@
+package fheap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFHeapMeta(t *testing.T) {
+	type retries struct{ count int }
+	h := intMinHeap[string]()
+	for i, v := range []string{"a", "b", "c"} {
+		if err := h.Push(v, i+1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if meta, err := h.GetMeta("a"); err != nil || meta != nil {
+		t.Fatalf("expected no metadata, got (%v, %v)", meta, err)
+	}
+	if err := h.SetMeta("b", &retries{3}); err != nil {
+		t.Fatal(err)
+	}
+	// lowering the priority keeps the metadata
+	if err := h.SetPriority("b", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.IncreasePriority("b", 0); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := h.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, heap := range []*Heap[string, int]{h, clone} {
+		if meta, err := heap.GetMeta("b"); err != nil {
+			t.Fatal(err)
+		} else if r, ok := meta.(*retries); !ok || r.count != 3 {
+			t.Fatalf("expected retries{3}, got %v", meta)
+		}
+	}
+	if _, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.GetMeta("b"); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
+	}
+	if err := h.SetMeta("z", 1); !errors.Is(err, ErrValueNotFound) {
+		t.Fatalf("expected %v, got %v", ErrValueNotFound, err)
+	}
+	var nilHeap *Heap[string, int]
+	if err := nilHeap.SetMeta("a", 1); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+}
@@ -0,0 +1,68 @@
+package fheap
+
+import (
+	"cmp"
+	"math"
+	"reflect"
+)
+
+// NewMin creates an empty Fibonacci heap which pops the lowest priority
+// first. The priority type's lowest value is reserved: NaN for floats, as
+// cmp.Less orders it before -Inf, and the minimum for other types.
+func NewMin[V comparable, P cmp.Ordered](opts ...Option[V, P]) *Heap[V, P] {
+	lowest, _ := extremeOf[P](false)
+	return New(cmp.Less[P], lowest, opts...)
+}
+
+// NewMax creates an empty Fibonacci heap which pops the highest priority
+// first. The priority type's highest value is reserved: +Inf for floats,
+// and the maximum for integers. Strings have no maximum, so max-heaps of
+// string priorities reserve none, as if created by NewUnreserved.
+func NewMax[V comparable, P cmp.Ordered](opts ...Option[V, P]) *Heap[V, P] {
+	higherThan := func(x, y P) bool { return cmp.Less(y, x) }
+	highest, ok := extremeOf[P](true)
+	if !ok {
+		return NewUnreserved(higherThan, opts...)
+	}
+	return New(higherThan, highest, opts...)
+}
+
+// NewOrdered creates an empty Fibonacci heap of ordered priorities, which
+// is a min-heap, as by NewMin, if `minHeap` is set, and a max-heap, as by
+// NewMax, otherwise.
+func NewOrdered[V comparable, P cmp.Ordered](minHeap bool, opts ...Option[V, P]) *Heap[V, P] {
+	if minHeap {
+		return NewMin(opts...)
+	}
+	return NewMax(opts...)
+}
+
+// extremeOf returns the value of P ordered first, or last if `last` is set,
+// by cmp.Less, reporting whether there is one.
+func extremeOf[P cmp.Ordered](last bool) (P, bool) {
+	v := reflect.New(reflect.TypeFor[P]()).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := v.Type().Bits()
+		if last {
+			v.SetInt(math.MaxInt64 >> (64 - bits))
+		} else {
+			v.SetInt(math.MinInt64 >> (64 - bits))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if last {
+			v.SetUint(math.MaxUint64 >> (64 - v.Type().Bits()))
+		}
+	case reflect.Float32, reflect.Float64:
+		if last {
+			v.SetFloat(math.Inf(1))
+		} else {
+			v.SetFloat(math.NaN())
+		}
+	case reflect.String:
+		if last {
+			return v.Interface().(P), false
+		}
+	}
+	return v.Interface().(P), true
+}
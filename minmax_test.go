@@ -0,0 +1,108 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestExtremeOf(t *testing.T) {
+	if lo, _ := extremeOf[int8](false); lo != math.MinInt8 {
+		t.Fatalf("expected %d, got %d", math.MinInt8, lo)
+	}
+	if hi, _ := extremeOf[int64](true); hi != math.MaxInt64 {
+		t.Fatalf("expected %d, got %d", int64(math.MaxInt64), hi)
+	}
+	if hi, _ := extremeOf[uint16](true); hi != math.MaxUint16 {
+		t.Fatalf("expected %d, got %d", math.MaxUint16, hi)
+	}
+	if lo, _ := extremeOf[float32](false); !math.IsNaN(float64(lo)) {
+		t.Fatalf("expected NaN, got %v", lo)
+	}
+	if _, ok := extremeOf[string](true); ok {
+		t.Fatal("expected strings to have no maximum")
+	}
+	type level int
+	if lo, _ := extremeOf[level](false); lo != math.MinInt {
+		t.Fatalf("expected %d, got %d", math.MinInt, lo)
+	}
+}
+
+func TestNewMinMax(t *testing.T) {
+	N := *HeapSize
+	lo, hi := NewMin[int, float64](), NewMax[int, float64]()
+	for _, p := range rand.Perm(N) {
+		if err := Push(lo, p, float64(p), t.Name()); err != nil {
+			t.Fatal(err)
+		}
+		if err := Push(hi, p, float64(p), t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := lo.Push(N, math.NaN()); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := hi.Push(N, math.Inf(1)); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := lo.Delete(N / 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := hi.Delete(N / 2); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < N; i++ {
+		if i == N/2 {
+			continue
+		}
+		if v, err := Pop(lo, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != i {
+			t.Fatalf("[NewMin] expected %d, got %d", i, v)
+		}
+	}
+	for i := N - 1; i >= 0; i-- {
+		if i == N/2 {
+			continue
+		}
+		if v, err := Pop(hi, t.Name()); err != nil {
+			t.Fatal(err)
+		} else if v != i {
+			t.Fatalf("[NewMax] expected %d, got %d", i, v)
+		}
+	}
+	if err := NewMax[int, string]().Delete(0); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+}
+
+func TestNewOrdered(t *testing.T) {
+	lo, hi := NewOrdered[string, int](true), NewOrdered[string, int](false)
+	if err := lo.Push("a", math.MinInt); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	if err := hi.Push("a", math.MaxInt); err != ErrReservedPriority {
+		t.Fatalf("expected %v, got %v", ErrReservedPriority, err)
+	}
+	for p, v := range []string{"a", "b", "c"} {
+		if err := lo.Push(v, p); err != nil {
+			t.Fatal(err)
+		}
+		if err := hi.Push(v, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, _ := lo.TryPeek(); v != "a" {
+		t.Fatalf("expected min-heap to peek a, got %s", v)
+	}
+	if v, _ := hi.TryPeek(); v != "c" {
+		t.Fatalf("expected max-heap to peek c, got %s", v)
+	}
+	strings := NewOrdered[int, string](false)
+	if err := strings.Push(0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := strings.Delete(0); err != nil {
+		t.Fatal(err)
+	}
+}
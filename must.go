@@ -0,0 +1,53 @@
+package fheap
+
+// The Must variants panic instead of returning an error, for code in which
+// a failing operation, e.g. popping an empty heap, is a programmer error.
+// They panic with the error the corresponding method returns.
+
+// must panics with err if it isn't nil.
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// MustPush is like Push but panics on error.
+func (fh *Heap[V, P]) MustPush(value V, priority P) {
+	must(fh.Push(value, priority))
+}
+
+// MustPop is like Pop but panics on error.
+func (fh *Heap[V, P]) MustPop() V {
+	value, err := fh.Pop()
+	must(err)
+	return value
+}
+
+// MustPopPair is like PopPair but panics on error.
+func (fh *Heap[V, P]) MustPopPair() (V, P) {
+	value, priority, err := fh.PopPair()
+	must(err)
+	return value, priority
+}
+
+// MustPeek is like Peek but panics on error.
+func (fh *Heap[V, P]) MustPeek() (V, P) {
+	value, priority, err := fh.Peek()
+	must(err)
+	return value, priority
+}
+
+// MustIncreasePriority is like IncreasePriority but panics on error.
+func (fh *Heap[V, P]) MustIncreasePriority(value V, priority P) {
+	must(fh.IncreasePriority(value, priority))
+}
+
+// MustSetPriority is like SetPriority but panics on error.
+func (fh *Heap[V, P]) MustSetPriority(value V, priority P) {
+	must(fh.SetPriority(value, priority))
+}
+
+// MustDelete is like Delete but panics on error.
+func (fh *Heap[V, P]) MustDelete(value V) {
+	must(fh.Delete(value))
+}
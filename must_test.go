@@ -0,0 +1,44 @@
+package fheap
+
+import (
+	"errors"
+	"testing"
+)
+
+func mustPanic(t *testing.T, target error, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		err, _ := recover().(error)
+		if !errors.Is(err, target) {
+			t.Fatalf("expected a panic with %v, got %v", target, err)
+		}
+	}()
+	f()
+}
+
+func TestFHeapMust(t *testing.T) {
+	h := intMinHeap[string]()
+	h.MustPush("a", 3)
+	h.MustPush("b", 2)
+	h.MustPush("c", 1)
+	h.MustIncreasePriority("a", 0)
+	h.MustSetPriority("c", 4)
+	if v, p := h.MustPeek(); v != "a" || p != 0 {
+		t.Fatalf("expected (a, 0), got (%s, %d)", v, p)
+	}
+	h.MustDelete("b")
+	if v, p := h.MustPopPair(); v != "a" || p != 0 {
+		t.Fatalf("expected (a, 0), got (%s, %d)", v, p)
+	}
+	if v := h.MustPop(); v != "c" {
+		t.Fatalf("expected c, got %s", v)
+	}
+	mustPanic(t, ErrEmptyHeap, func() { h.MustPop() })
+	mustPanic(t, ErrEmptyHeap, func() { h.MustPeek() })
+	h.MustPush("a", 1)
+	mustPanic(t, ErrValueNotFound, func() { h.MustDelete("b") })
+	mustPanic(t, ErrDuplicateValue, func() { h.MustPush("a", 2) })
+	var nilHeap *Heap[string, int]
+	mustPanic(t, ErrNilHeap, func() { nilHeap.MustPush("a", 1) })
+}
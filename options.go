@@ -0,0 +1,115 @@
+package fheap
+
+import "unique"
+
+// Option configures a heap created by New.
+type Option[V comparable, P any] func(*Heap[V, P])
+
+// DuplicatePolicy determines how Push handles a value already in the heap.
+type DuplicatePolicy int
+
+const (
+	// DuplicatesError makes Push return an error for duplicate values.
+	DuplicatesError DuplicatePolicy = iota
+	// DuplicatesReplace makes Push overwrite the value's priority.
+	DuplicatesReplace
+	// DuplicatesKeepBest makes Push retain whichever priority is higher.
+	DuplicatesKeepBest
+	// DuplicatesAllow makes Push insert another occurrence of the value,
+	// making the heap a multiset.
+	DuplicatesAllow
+)
+
+// WithDuplicatePolicy sets how Push handles values already in the heap.
+// The default is DuplicatesError.
+func WithDuplicatePolicy[V comparable, P any](policy DuplicatePolicy) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.duplicates = policy
+	}
+}
+
+// WithAllowDuplicates makes the heap a multiset, in which a value may be
+// pushed more than once. Unlike WithoutIndex, values stay indexed, and
+// operations taking a value, such as IncreasePriority or Delete, act on
+// one of its occurrences. DiffSince and ApplyDiff, which track changes by
+// value, are unsupported.
+func WithAllowDuplicates[V comparable, P any]() Option[V, P] {
+	return WithDuplicatePolicy[V, P](DuplicatesAllow)
+}
+
+// WithCapacityHint sizes the heap's value index for about `n` values, to
+// avoid rehashing while the heap grows to that size. It has no effect on
+// heaps created with WithoutIndex.
+func WithCapacityHint[V comparable, P any](n int) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		if fh.unindexed {
+			return
+		}
+		fh.values = make(map[V]*fnode[V, P], max(n, 0))
+		fh.peak = max(n, 0)
+	}
+}
+
+// WithInterner makes Push intern values with `intern` before storing them,
+// so that equal values share storage. This cuts memory for heaps keyed by
+// long strings, such as URLs or paths.
+func WithInterner[V comparable, P any](intern func(value V) V) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.intern = intern
+	}
+}
+
+// UniqueInterner returns an interner canonicalising values using the
+// unique package, for use with WithInterner.
+func UniqueInterner[V comparable]() func(value V) V {
+	return func(value V) V {
+		return unique.Make(value).Value()
+	}
+}
+
+// WithFIFOTies makes the heap break ties between equal priorities by
+// insertion order, so that values with equal priorities are popped first
+// in, first out. Values keep their place in insertion order when their
+// priority changes.
+func WithFIFOTies[V comparable, P any]() Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.fifo = true
+	}
+}
+
+// WithPriorityEqual makes the heap test priorities for equality with
+// `equal`, rather than by comparing them both ways with `higherThan`. It's
+// used to recognise the reserved priority and to break FIFO ties. `equal`
+// must not consider two priorities equal if one is higher than the other.
+func WithPriorityEqual[V comparable, P any](equal func(a, b P) bool) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.equal = equal
+	}
+}
+
+// WithPriorityValidator makes operations setting a value's priority, such
+// as Push and IncreasePriority, fail with the error `validate` returns for
+// the priority, if any.
+func WithPriorityValidator[V comparable, P any](validate func(priority P) error) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.validate = validate
+	}
+}
+
+// WithDeterministicDump makes the heap's snapshots, namely All, DiffSince
+// and MarshalBinary, list values by priority, breaking ties by insertion
+// order, rather than in map order, so that consecutive snapshots can be
+// diffed. Snapshots then take O(n log n) time.
+func WithDeterministicDump[V comparable, P any]() Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.deterministic = true
+	}
+}
+
+// WithPushTimestamps makes the heap record when each value was pushed,
+// exposed by PeekNode.
+func WithPushTimestamps[V comparable, P any]() Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.timestamps = true
+	}
+}
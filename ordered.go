@@ -0,0 +1,246 @@
+package fheap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// OrderedHeap is a Fibonacci heap specialised for ordered priorities, where
+// lower priorities are higher, i.e. a min-heap. Priorities are compared
+// directly using `<` rather than through a comparison function, which
+// allows the comparisons to be inlined.
+// Since `Delete` cuts the value's node directly, no priority is reserved.
+// Like Heap, it reuses the degree table of its consolidations.
+type OrderedHeap[V comparable, P cmp.Ordered] struct {
+	prioritaire *fnode[V, P]
+	values      map[V]*fnode[V, P]
+	scratch     []*fnode[V, P]
+}
+
+// NewOrderedHeap creates an empty OrderedHeap.
+func NewOrderedHeap[V comparable, P cmp.Ordered]() *OrderedHeap[V, P] {
+	return &OrderedHeap[V, P]{values: map[V]*fnode[V, P]{}}
+}
+
+// Size returns the number of elements in the heap.
+func (oh *OrderedHeap[V, P]) Size() (int, error) {
+	if oh == nil {
+		return 0, ErrNilHeap
+	}
+	return len(oh.values), nil
+}
+
+// Push inserts a given value with the supplied priority into the heap.
+func (oh *OrderedHeap[V, P]) Push(value V, priority P) error {
+	if oh == nil {
+		return ErrNilHeap
+	}
+	if _, ok := oh.values[value]; ok {
+		return &OpError[V, P]{"Push", value, priority, ErrDuplicateValue}
+	}
+	node := newFnode(value, priority)
+	oh.values[value] = node
+	if oh.prioritaire == nil {
+		oh.prioritaire = node
+		return nil
+	}
+	if err := oh.prioritaire.insertLeft(node); err != nil {
+		return err
+	}
+	if priority < oh.prioritaire.priority {
+		oh.prioritaire = node
+	}
+	return nil
+}
+
+// Pop removes and returns the highest-priority element from the heap
+// after consolidating the heap.
+func (oh *OrderedHeap[V, P]) Pop() (value V, err error) {
+	if oh == nil {
+		return value, ErrNilHeap
+	}
+	if oh.prioritaire == nil {
+		return value, ErrEmptyHeap
+	}
+	value = oh.prioritaire.value
+	// foster out prioritaire's children
+	for {
+		child, err := oh.prioritaire.popChild()
+		if err != nil {
+			if err == errBarrenFnode {
+				break
+			}
+			return value, err
+		}
+		child.parent = nil
+		child.left = child
+		child.right = child
+		child.bereaved = false
+		if err := oh.prioritaire.insertLeft(child); err != nil {
+			return value, err
+		}
+	}
+	delete(oh.values, value)
+	// remove prioritaire from the heap's root list
+	if oh.prioritaire.left == oh.prioritaire {
+		oh.prioritaire = nil
+		return value, nil
+	}
+	oh.prioritaire.left.right = oh.prioritaire.right
+	oh.prioritaire.right.left = oh.prioritaire.left
+	oh.prioritaire = oh.prioritaire.right
+	return value, oh.consolidate()
+}
+
+// TryPop pops the highest-priority value from the heap, reporting whether
+// there was one to pop.
+func (oh *OrderedHeap[V, P]) TryPop() (V, bool) {
+	value, err := oh.Pop()
+	return value, err == nil
+}
+
+// TryPeek returns the highest-priority value in the heap without removing
+// it, reporting whether there was one.
+func (oh *OrderedHeap[V, P]) TryPeek() (value V, ok bool) {
+	if oh == nil || oh.prioritaire == nil {
+		return
+	}
+	return oh.prioritaire.value, true
+}
+
+// IncreasePriority increases a value's priority in the heap, if present,
+// i.e. lowers it.
+func (oh *OrderedHeap[V, P]) IncreasePriority(value V, priority P) error {
+	if oh == nil {
+		return ErrNilHeap
+	}
+	if oh.prioritaire == nil {
+		return ErrEmptyHeap
+	}
+	x, ok := oh.values[value]
+	if !ok {
+		return &OpError[V, P]{"IncreasePriority", value, priority, ErrValueNotFound}
+	}
+	if x.priority < priority {
+		return &OpError[V, P]{"IncreasePriority", value, priority, fmt.Errorf("%w %v", ErrLowerPriority, x.priority)}
+	}
+	x.priority = priority
+	if y := x.parent; y != nil && x.priority < y.priority {
+		if err := oh.detach(x); err != nil {
+			return err
+		}
+	}
+	if x.priority < oh.prioritaire.priority {
+		oh.prioritaire = x
+	}
+	return nil
+}
+
+// Delete deletes a value from the heap, if present, by moving its node to
+// the root list and popping it.
+func (oh *OrderedHeap[V, P]) Delete(value V) error {
+	if oh == nil {
+		return ErrNilHeap
+	}
+	if oh.prioritaire == nil {
+		return ErrEmptyHeap
+	}
+	x, ok := oh.values[value]
+	if !ok {
+		return &OpError[V, P]{Op: "Delete", Value: value, Err: ErrValueNotFound}
+	}
+	if x.parent != nil {
+		if err := oh.detach(x); err != nil {
+			return err
+		}
+	}
+	oh.prioritaire = x
+	_, err := oh.Pop()
+	return err
+}
+
+// consolidate reduces the number of trees in the heap.
+func (oh *OrderedHeap[V, P]) consolidate() error {
+	size := degreeBound(len(oh.values)) + 1
+	if cap(oh.scratch) < size {
+		oh.scratch = make([]*fnode[V, P], size)
+	}
+	A := oh.scratch[:size]
+	// the table is cleared for the next consolidation, and so as not to
+	// keep nodes alive after they're removed from the heap
+	defer clear(A)
+	end := oh.prioritaire.left
+	for w := oh.prioritaire; ; {
+		next := w.right
+		x := w
+		d := x.degree
+		for A[d] != nil {
+			y := A[d]
+			if y.priority < x.priority {
+				x, y = y, x
+			}
+			// remove y from the root list, and make it a child of x
+			y.left.right = y.right
+			y.right.left = y.left
+			y.left = y
+			y.right = y
+			if err := x.insertChild(y); err != nil {
+				return err
+			}
+			y.bereaved = false
+			A[d] = nil
+			d++
+		}
+		A[d] = x
+		if w == end {
+			break
+		}
+		w = next
+	}
+	// find the new minimum
+	oh.prioritaire = nil
+	for _, root := range A {
+		if root == nil {
+			continue
+		}
+		root.left.right = root.right
+		root.right.left = root.left
+		root.left = root
+		root.right = root
+		if oh.prioritaire == nil {
+			oh.prioritaire = root
+			continue
+		}
+		if err := oh.prioritaire.insertLeft(root); err != nil {
+			return err
+		}
+		if root.priority < oh.prioritaire.priority {
+			oh.prioritaire = root
+		}
+	}
+	return nil
+}
+
+// detach cuts x from its parent, turning it into a root, and performs the
+// ancestral cascading cuts.
+func (oh *OrderedHeap[V, P]) detach(x *fnode[V, P]) error {
+	for y := x.parent; y != nil; x, y = y, y.parent {
+		if err := y.removeChild(x); err != nil {
+			return err
+		}
+		x.left = x
+		x.right = x
+		x.parent = nil
+		x.bereaved = false
+		if err := oh.prioritaire.insertLeft(x); err != nil {
+			return err
+		}
+		if !y.bereaved {
+			if y.parent != nil {
+				y.bereaved = true
+			}
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package fheap
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func isOrderedFibonacciHeap[V comparable, P cmp.Ordered](oh *OrderedHeap[V, P]) error {
+	fh := &Heap[V, P]{
+		prioritaire: oh.prioritaire,
+		values:      oh.values,
+		higherThan:  func(x, y P) bool { return x < y },
+	}
+	// OrderedHeap doesn't count its bereaved nodes, nor track its degrees
+	for _, x := range oh.values {
+		if x.bereaved {
+			fh.marked++
+		}
+		fh.maxDegree = max(fh.maxDegree, x.degree)
+	}
+	return fh.checkInvariants()
+}
+
+func TestOrderedHeap(t *testing.T) {
+	h := NewOrderedHeap[int, int]()
+	if _, err := h.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p+N); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Push(0, 0); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if v, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	} else if v != 0 {
+		t.Fatalf("expected value=0, got %d", v)
+	}
+	if err := h.IncreasePriority(N-1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.IncreasePriority(N-2, 3*N); err == nil {
+		t.Fatal("expected lowering priority to fail")
+	}
+	if err := h.Delete(N / 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := isOrderedFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{N - 1}
+	for v := 1; v < N-1; v++ {
+		if v != N/2 {
+			expected = append(expected, v)
+		}
+	}
+	for _, e := range expected {
+		if v, err := h.Pop(); err != nil {
+			t.Fatal(err)
+		} else if v != e {
+			t.Fatalf("expected value=%d, got %d", e, v)
+		}
+		if err := isOrderedFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s, _ := h.Size(); s != 0 {
+		t.Fatalf("expected empty heap, got size=%d", s)
+	}
+}
+
+func BenchmarkFHeapPushPop(b *testing.B) {
+	h := intMinHeap[int]()
+	perm := rand.Perm(1 << 10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range perm {
+			h.Push(p, p)
+		}
+		for range perm {
+			h.Pop()
+		}
+	}
+}
+
+func BenchmarkOrderedHeapPushPop(b *testing.B) {
+	h := NewOrderedHeap[int, int]()
+	perm := rand.Perm(1 << 10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range perm {
+			h.Push(p, p)
+		}
+		for range perm {
+			h.Pop()
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package fheap
+
+import "sync"
+
+// WithNodePool makes the heap recycle the nodes of popped and deleted
+// values through a sync.Pool, so that workloads pushing and popping many
+// short-lived values allocate fewer nodes. Since a recycled node may come
+// to hold another value, PushHandle is unsupported by such heaps.
+func WithNodePool[V comparable, P any]() Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.pool = new(sync.Pool)
+	}
+}
+
+// acquire returns a node holding the given value and priority, reusing a
+// recycled node if one is available.
+func (fh *Heap[V, P]) acquire(value V, priority P) *fnode[V, P] {
+	if fh.pool == nil {
+		return newFnode(value, priority)
+	}
+	node, ok := fh.pool.Get().(*fnode[V, P])
+	if !ok {
+		return newFnode(value, priority)
+	}
+	node.value, node.priority = value, priority
+	node.left, node.right = node, node
+	return node
+}
+
+// release recycles a node removed from the heap, if the heap pools nodes.
+// The node is zeroed so as not to keep its value and metadata alive.
+func (fh *Heap[V, P]) release(node *fnode[V, P]) {
+	if fh.pool == nil {
+		return
+	}
+	*node = fnode[V, P]{}
+	fh.pool.Put(node)
+}
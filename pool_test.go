@@ -0,0 +1,88 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func nodePoolHeap() *Heap[int, int] {
+	return New(func(x, y int) bool { return x < y }, math.MinInt, WithNodePool[int, int]())
+}
+
+func TestFHeapNodePool(t *testing.T) {
+	h := nodePoolHeap()
+	N := *HeapSize
+	for round := range 3 {
+		for _, p := range rand.Perm(N) {
+			if err := Push(h, p, p, t.Name()); err != nil {
+				t.Fatal(err)
+			}
+			if err := h.SetMeta(p, round); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for v := 0; v < N; v += 3 {
+			if err := h.Delete(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := isFibonacciHeap(h); err != nil {
+			t.Fatal(err)
+		}
+		for v := range N {
+			if v%3 == 0 {
+				continue
+			}
+			if popped, err := Pop(h, t.Name()); err != nil {
+				t.Fatal(err)
+			} else if popped != v {
+				t.Fatalf("[round %d] expected %d, got %d", round, v, popped)
+			}
+			if meta, err := h.GetMeta(v); err == nil {
+				t.Fatalf("expected popped value %d to lose its metadata, got %v", v, meta)
+			}
+		}
+	}
+	if _, err := h.PushHandle(0, 0); err != ErrUnsupported {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+	if _, err := h.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Push(h, 0, 0, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFHeapNodePool_Release(t *testing.T) {
+	h := nodePoolHeap()
+	if err := h.Push(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetMeta(1, "meta"); err != nil {
+		t.Fatal(err)
+	}
+	node := h.values[1]
+	if _, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if node.value != 0 || node.meta() != nil || node.left != nil {
+		t.Fatal("expected the released node to be zeroed")
+	}
+}
+
+func BenchmarkFHeapPushPop_NodePool(b *testing.B) {
+	h := nodePoolHeap()
+	perm := rand.Perm(1 << 10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range perm {
+			h.Push(p, p)
+		}
+		for range perm {
+			h.Pop()
+		}
+	}
+}
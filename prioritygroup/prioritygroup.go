@@ -0,0 +1,150 @@
+// Package prioritygroup provides errgroup-style goroutine groups whose
+// queued functions are started in priority order, with bounded concurrency.
+package prioritygroup
+
+import (
+	"context"
+	"sync"
+
+	fheap "github.com/iyassou/fibonacci-heap"
+)
+
+// task is a function queued in a Group.
+type task[P any] struct {
+	priority P
+	f        func(ctx context.Context) error
+}
+
+// Group is a collection of goroutines working on subtasks of a common
+// task. Functions are queued in a Fibonacci heap, and the highest-priority
+// queued function is started whenever the concurrency limit allows, with
+// functions of equal priority started in the order they were queued.
+type Group[P any] struct {
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	higherThan func(x, y P) bool
+
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	queue  *fheap.Heap[uint64, P]
+	tasks  map[uint64]task[P]
+	next   uint64
+	limit  int
+	active int
+	err    error
+}
+
+// WithContext returns a new Group and an associated context derived from
+// ctx, which is cancelled the first time a function returns a non-nil
+// error or the first time Wait returns. `higherThan` determines if the
+// first priority is higher than the second.
+func WithContext[P any](ctx context.Context, higherThan func(x, y P) bool) (*Group[P], context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group[P]{
+		ctx:        ctx,
+		cancel:     cancel,
+		higherThan: higherThan,
+		queue:      fheap.NewUnreserved(higherThan, fheap.WithFIFOTies[uint64, P]()),
+		tasks:      map[uint64]task[P]{},
+		limit:      -1,
+	}, ctx
+}
+
+// SetLimit limits the number of active goroutines in the group to at most
+// n. A negative value indicates no limit.
+func (g *Group[P]) SetLimit(n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limit = n
+	g.dispatch()
+}
+
+// Go queues the given function to be called in a new goroutine with the
+// group's context, once no higher-priority function is queued and the
+// concurrency limit allows. Functions queued after a function returned an
+// error are discarded.
+func (g *Group[P]) Go(priority P, f func(ctx context.Context) error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return
+	}
+	id := g.next
+	g.next++
+	if err := g.queue.Push(id, priority); err != nil {
+		g.fail(err)
+		return
+	}
+	g.wg.Add(1)
+	g.tasks[id] = task[P]{priority, f}
+	g.dispatch()
+}
+
+// CancelBelow discards queued functions whose priority is lower than the
+// given priority, returning how many were discarded.
+func (g *Group[P]) CancelBelow(priority P) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cancelled := 0
+	for id, t := range g.tasks {
+		if g.higherThan(priority, t.priority) {
+			delete(g.tasks, id)
+			g.queue.Delete(id)
+			g.wg.Done()
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
+// Wait blocks until all started functions have returned and no functions
+// remain queued, then returns the first non-nil error (if any) from them.
+func (g *Group[P]) Wait() error {
+	g.wg.Wait()
+	g.cancel(g.err)
+	return g.err
+}
+
+// dispatch starts the highest-priority queued functions while the
+// concurrency limit allows.
+func (g *Group[P]) dispatch() {
+	for len(g.tasks) > 0 && (g.limit < 0 || g.active < g.limit) {
+		id, err := g.queue.Pop()
+		if err != nil {
+			g.fail(err)
+			return
+		}
+		t := g.tasks[id]
+		delete(g.tasks, id)
+		g.active++
+		go g.run(t)
+	}
+}
+
+// run calls a task's function, recording its error.
+func (g *Group[P]) run(t task[P]) {
+	err := t.f(g.ctx)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	defer g.wg.Done()
+	g.active--
+	if err != nil {
+		g.fail(err)
+	}
+	g.dispatch()
+}
+
+// fail records the group's first error, cancelling its context and
+// discarding its queued functions.
+func (g *Group[P]) fail(err error) {
+	if g.err != nil {
+		return
+	}
+	g.err = err
+	g.cancel(err)
+	for id := range g.tasks {
+		delete(g.tasks, id)
+		g.wg.Done()
+	}
+	g.queue.Clear()
+}
@@ -0,0 +1,91 @@
+package prioritygroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func lower(x, y int) bool { return x < y }
+
+func TestGroupPriorityOrder(t *testing.T) {
+	g, _ := WithContext(context.Background(), lower)
+	g.SetLimit(1)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []int
+	g.Go(0, func(context.Context) error {
+		<-release
+		return nil
+	})
+	for _, p := range []int{3, 1, 4, 2, 5} {
+		g.Go(p, func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, p)
+			return nil
+		})
+	}
+	if n := g.CancelBelow(4); n != 1 {
+		t.Fatalf("expected 1 cancelled function, got %d", n)
+	}
+	g.mu.Lock()
+	if n, _ := g.queue.Size(); n != len(g.tasks) {
+		t.Fatalf("expected %d queued ids, got %d", len(g.tasks), n)
+	}
+	g.mu.Unlock()
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := fmt.Sprint(order); actual != "[1 2 3 4]" {
+		t.Fatalf("expected [1 2 3 4], got %s", actual)
+	}
+}
+
+func TestGroupQueueOrder(t *testing.T) {
+	g, _ := WithContext(context.Background(), lower)
+	g.SetLimit(1)
+	release := make(chan struct{})
+	var order []int
+	g.Go(0, func(context.Context) error {
+		<-release
+		return nil
+	})
+	for i := 0; i < 8; i++ {
+		g.Go(1, func(context.Context) error {
+			order = append(order, i)
+			return nil
+		})
+	}
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := fmt.Sprint(order); actual != "[0 1 2 3 4 5 6 7]" {
+		t.Fatalf("expected [0 1 2 3 4 5 6 7], got %s", actual)
+	}
+}
+
+func TestGroupFirstError(t *testing.T) {
+	g, ctx := WithContext(context.Background(), lower)
+	g.SetLimit(1)
+	boom := errors.New("boom")
+	ran := false
+	g.Go(1, func(context.Context) error { return boom })
+	g.Go(2, func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if err := g.Wait(); err != boom {
+		t.Fatalf("expected %v, got %v", boom, err)
+	}
+	if ran {
+		t.Fatal("expected queued function to be discarded")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("expected context to be cancelled")
+	}
+}
@@ -0,0 +1,196 @@
+package fheap
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+// PeekK returns up to k of the highest-priority elements in the heap, in
+// priority order, without modifying the heap.
+// Candidates are kept in an auxiliary heap, seeded with the roots, to which
+// each element's children are added as it's taken.
+func (fh *Heap[V, P]) PeekK(k int) ([]Item[V, P], error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return nil, fh.corrupted
+	}
+	nodes, err := fh.top(k)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item[V, P], len(nodes))
+	for i, x := range nodes {
+		items[i] = Item[V, P]{x.value, x.priority}
+	}
+	return items, nil
+}
+
+// ToSortedSlice returns every element in the heap in priority order,
+// without modifying the heap.
+func (fh *Heap[V, P]) ToSortedSlice() ([]Item[V, P], error) {
+	return fh.PeekK(fh.Len())
+}
+
+// Kth returns the k-th highest-priority element in the heap, counting from
+// 1, without modifying the heap. It takes O(k log k) time, as for PeekK.
+func (fh *Heap[V, P]) Kth(k int) (value V, priority P, err error) {
+	if fh == nil {
+		return value, priority, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return value, priority, fh.corrupted
+	}
+	if fh.prioritaire == nil {
+		return value, priority, ErrEmptyHeap
+	}
+	if k < 1 || k > fh.len() {
+		return value, priority, fmt.Errorf("k=%d out of range [1, %d]", k, fh.len())
+	}
+	nodes, err := fh.top(k)
+	if err != nil {
+		return value, priority, err
+	}
+	x := nodes[k-1]
+	return x.value, x.priority, nil
+}
+
+// Walk visits every element in the heap in depth-first order, starting
+// from the highest-priority root, calling fn with each element's depth in
+// its tree, and its parent's value, or nil for roots. Walk stops if fn
+// returns false. The heap mustn't be modified during the walk.
+func (fh *Heap[V, P]) Walk(fn func(value V, priority P, depth int, parent *V) bool) error {
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.prioritaire == nil {
+		return nil
+	}
+	// each frame holds the next node to visit in a ring of siblings
+	type frame struct {
+		start, x *fnode[V, P]
+		depth    int
+		parent   *V
+	}
+	stack := []frame{{start: fh.prioritaire, x: fh.prioritaire}}
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		x, depth := f.x, f.depth
+		if !fn(x.value, x.priority, depth, f.parent) {
+			return nil
+		}
+		if x.right == f.start {
+			stack = stack[:len(stack)-1]
+		} else {
+			f.x = x.right
+		}
+		if x.children != nil {
+			value := x.value
+			stack = append(stack, frame{x.children, x.children, depth + 1, &value})
+		}
+	}
+	return nil
+}
+
+// Equal reports whether two heaps hold the same values with equal
+// priorities, regardless of their structure. Priorities are compared with
+// the heap's comparison function. Nil heaps are only equal to each other.
+func (fh *Heap[V, P]) Equal(other *Heap[V, P]) bool {
+	if fh == nil || other == nil {
+		return fh == other
+	}
+	if fh.len() != other.len() {
+		return false
+	}
+	if fh.allowsDuplicates() || other.allowsDuplicates() {
+		// values may be repeated, so they're matched one occurrence at a time
+		unmatched := make(map[V][]P, fh.len())
+		for x := range fh.nodes() {
+			unmatched[x.value] = append(unmatched[x.value], x.priority)
+		}
+		for y := range other.nodes() {
+			priorities := unmatched[y.value]
+			i := slices.IndexFunc(priorities, func(p P) bool { return fh.prioritiesEqual(p, y.priority) })
+			if i < 0 {
+				return false
+			}
+			unmatched[y.value] = slices.Delete(priorities, i, i+1)
+		}
+		return true
+	}
+	for value, x := range fh.values {
+		y, ok := other.values[value]
+		if !ok || !fh.prioritiesEqual(x.priority, y.priority) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetPriorities returns the priorities of the given values present in the
+// heap, and the values missing from it, in the order given. A nil heap is
+// missing every value.
+func (fh *Heap[V, P]) GetPriorities(values []V) (priorities map[V]P, missing []V) {
+	priorities = make(map[V]P, len(values))
+	if fh == nil {
+		return priorities, append(missing, values...)
+	}
+	for _, value := range values {
+		if x, ok := fh.values[value]; ok {
+			priorities[value] = x.priority
+		} else {
+			missing = append(missing, value)
+		}
+	}
+	return priorities, missing
+}
+
+// top returns up to k of the highest-priority nodes in the heap, in
+// priority order.
+func (fh *Heap[V, P]) top(k int) (nodes []*fnode[V, P], err error) {
+	defer fh.recoverPanic(&err)
+	k = min(k, fh.len())
+	if k <= 0 {
+		return nil, nil
+	}
+	candidates := &nodeQueue[V, P]{higherThan: fh.nodeHigherThan}
+	push := func(start *fnode[V, P]) {
+		if start == nil {
+			return
+		}
+		for x := start; ; x = x.right {
+			heap.Push(candidates, x)
+			if x.right == start {
+				return
+			}
+		}
+	}
+	push(fh.prioritaire)
+	nodes = make([]*fnode[V, P], 0, k)
+	for len(nodes) < k {
+		x := heap.Pop(candidates).(*fnode[V, P])
+		nodes = append(nodes, x)
+		push(x.children)
+	}
+	return nodes, nil
+}
+
+// nodeQueue is a binary heap of nodes implementing heap.Interface, for
+// auxiliary queues whose values are the heap's nodes themselves.
+type nodeQueue[V comparable, P any] struct {
+	nodes      []*fnode[V, P]
+	higherThan func(x, y *fnode[V, P]) bool
+}
+
+func (q *nodeQueue[V, P]) Len() int           { return len(q.nodes) }
+func (q *nodeQueue[V, P]) Less(i, j int) bool { return q.higherThan(q.nodes[i], q.nodes[j]) }
+func (q *nodeQueue[V, P]) Swap(i, j int)      { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+func (q *nodeQueue[V, P]) Push(x any)         { q.nodes = append(q.nodes, x.(*fnode[V, P])) }
+
+func (q *nodeQueue[V, P]) Pop() any {
+	x := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return x
+}
@@ -0,0 +1,199 @@
+package fheap
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestFHeapPeekK(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	var expected []Item[int, int]
+	clone, err := h.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for clone.Len() > 0 {
+		v, p, err := clone.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, Item[int, int]{v, p})
+	}
+	for _, k := range []int{0, 1, N / 2, N, N + 1} {
+		items, err := h.PeekK(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != min(k, N) {
+			t.Fatalf("PeekK(%d): expected %d items, got %d", k, min(k, N), len(items))
+		}
+		for i, item := range items {
+			if item.Priority != expected[i].Priority {
+				t.Fatalf("PeekK(%d): expected item %d to be %v, got %v", k, i, expected[i], item)
+			}
+		}
+	}
+	if h.Len() != N {
+		t.Fatalf("expected PeekK not to modify the heap, got size=%d", h.Len())
+	}
+	if err := isFibonacciHeap(h); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFHeapKth(t *testing.T) {
+	h := intMinHeap[int]()
+	if _, _, err := h.Kth(1); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	h = scrambledHeap(t, N)
+	items, err := h.PeekK(N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 1; k <= N; k++ {
+		_, p, err := h.Kth(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != items[k-1].Priority {
+			t.Fatalf("Kth(%d): expected priority %d, got %d", k, items[k-1].Priority, p)
+		}
+	}
+	for _, k := range []int{0, N + 1} {
+		if _, _, err := h.Kth(k); err == nil {
+			t.Fatalf("Kth(%d): expected out of range error", k)
+		}
+	}
+}
+
+func TestFHeapToSortedSlice(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	if _, err := nilHeap.ToSortedSlice(); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	items, err := h.ToSortedSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != N {
+		t.Fatalf("expected %d items, got %d", N, len(items))
+	}
+	for _, item := range items {
+		v, p, err := h.PopPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != item.Priority || v != item.Value {
+			t.Fatalf("expected %v, got (%d, %d)", item, v, p)
+		}
+	}
+}
+
+func TestFHeapWalk(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	parents := map[int]*int{}
+	maxDepth := 0
+	err := h.Walk(func(v, p, depth int, parent *int) bool {
+		if _, ok := parents[v]; ok {
+			t.Fatalf("visited %d twice", v)
+		}
+		parents[v] = parent
+		maxDepth = max(maxDepth, depth)
+		if (parent == nil) != (depth == 0) {
+			t.Fatalf("value %d at depth %d has parent %v", v, depth, parent)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parents) != N {
+		t.Fatalf("expected %d values visited, got %d", N, len(parents))
+	}
+	for v, parent := range parents {
+		if parent == nil {
+			continue
+		}
+		if x := h.values[v]; x.parent == nil || x.parent.value != *parent {
+			t.Fatalf("value %d reported with parent %d", v, *parent)
+		}
+	}
+	if maxDepth == 0 {
+		t.Fatal("expected the heap to have trees of depth > 0")
+	}
+	visited := 0
+	if err := h.Walk(func(int, int, int, *int) bool { visited++; return visited < 3 }); err != nil {
+		t.Fatal(err)
+	}
+	if visited != 3 {
+		t.Fatalf("expected the walk to stop after 3 values, got %d", visited)
+	}
+}
+
+func TestFHeapEqual(t *testing.T) {
+	var nilHeap *Heap[int, int]
+	if !nilHeap.Equal(nil) {
+		t.Fatal("expected nil heaps to be equal")
+	}
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	if h.Equal(nil) || nilHeap.Equal(h) {
+		t.Fatal("expected nil and non-nil heaps to differ")
+	}
+	items, err := h.ToSortedSlice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewFromItems(func(x, y int) bool { return x < y }, math.MinInt, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.Equal(other) || !other.Equal(h) {
+		t.Fatal("expected heaps with the same contents to be equal")
+	}
+	if err := other.IncreasePriority(items[N-1].Value, 0); err != nil {
+		t.Fatal(err)
+	}
+	if h.Equal(other) {
+		t.Fatal("expected heaps with different priorities to differ")
+	}
+	if _, err := other.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if h.Equal(other) {
+		t.Fatal("expected heaps with different sizes to differ")
+	}
+}
+
+func TestFHeapGetPriorities(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	values := []int{0, 1, N / 2, N, N + 1, -1}
+	priorities, missing := h.GetPriorities(values)
+	if len(priorities) != 3 {
+		t.Fatalf("expected 3 priorities, got %v", priorities)
+	}
+	for _, v := range []int{1, N / 2, N} {
+		p, ok := priorities[v]
+		if !ok {
+			t.Fatalf("expected the priority of %d", v)
+		}
+		if expected := h.values[v].priority; p != expected {
+			t.Fatalf("expected the priority of %d to be %d, got %d", v, expected, p)
+		}
+	}
+	if !slices.Equal(missing, []int{0, N + 1, -1}) {
+		t.Fatalf("expected missing values [0 %d -1], got %v", N+1, missing)
+	}
+	var nilHeap *Heap[int, int]
+	if priorities, missing := nilHeap.GetPriorities(values); len(priorities) != 0 || !slices.Equal(missing, values) {
+		t.Fatalf("expected every value to be missing, got (%v, %v)", priorities, missing)
+	}
+}
@@ -0,0 +1,138 @@
+// Package ratelimit provides a token-bucket rate limiter whose waiters are
+// released in priority order, so that scarce capacity goes to the most
+// important waiters first.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	fheap "github.com/iyassou/fibonacci-heap"
+)
+
+// ErrInvalidRate is the panic value of New given a non-positive rate or a
+// burst too small to ever hold a token.
+var ErrInvalidRate = errors.New("ratelimit: rate and burst must be positive")
+
+// Limiter is a token bucket refilled at a fixed rate, up to a burst size.
+// Callers wait for a token in a Fibonacci heap ordered by their priority,
+// callers of equal priority being served in arrival order.
+type Limiter[P any] struct {
+	mu      sync.Mutex
+	rate    float64 // tokens per second
+	burst   float64
+	tokens  float64
+	last    time.Time
+	waiters *fheap.Heap[uint64, P]
+	tickets map[uint64]chan struct{}
+	next    uint64
+	timer   *time.Timer
+}
+
+// New creates a Limiter releasing `rate` tokens per second, holding at most
+// `burst` tokens. The bucket starts full. `higherThan` determines if the
+// first priority is higher than the second. New panics with ErrInvalidRate
+// if `rate` isn't positive or `burst` is less than one.
+func New[P any](rate float64, burst int, higherThan func(x, y P) bool) *Limiter[P] {
+	if !(rate > 0) || burst < 1 {
+		panic(ErrInvalidRate)
+	}
+	return &Limiter[P]{
+		rate:    rate,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+		waiters: fheap.NewUnreserved(higherThan, fheap.WithFIFOTies[uint64, P]()),
+		tickets: map[uint64]chan struct{}{},
+	}
+}
+
+// Wait blocks until a token is available for a caller of the given
+// priority, or the context is done. Tokens go to the highest-priority
+// waiter first, and to the longest-waiting of equal-priority waiters.
+func (l *Limiter[P]) Wait(ctx context.Context, priority P) error {
+	l.mu.Lock()
+	l.refill()
+	if len(l.tickets) == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+	ticket := l.next
+	l.next++
+	granted := make(chan struct{})
+	if err := l.waiters.Push(ticket, priority); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	l.tickets[ticket] = granted
+	l.dispatch()
+	l.mu.Unlock()
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-granted:
+		// the token was granted while the context was being cancelled
+		return nil
+	default:
+	}
+	delete(l.tickets, ticket)
+	l.waiters.Delete(ticket)
+	return ctx.Err()
+}
+
+// Waiting returns the number of callers waiting for a token.
+func (l *Limiter[P]) Waiting() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.tickets)
+}
+
+// refill adds the tokens accrued since the last refill to the bucket.
+func (l *Limiter[P]) refill() {
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// dispatch grants available tokens to the highest-priority waiters, and
+// schedules the next dispatch if any waiters remain.
+func (l *Limiter[P]) dispatch() {
+	l.refill()
+	for l.tokens >= 1 && len(l.tickets) > 0 {
+		ticket, err := l.waiters.Pop()
+		if err != nil {
+			return
+		}
+		granted := l.tickets[ticket]
+		delete(l.tickets, ticket)
+		close(granted)
+		l.tokens--
+	}
+	if len(l.tickets) == 0 {
+		return
+	}
+	wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if l.timer == nil {
+		l.timer = time.AfterFunc(wait, l.tick)
+	} else {
+		l.timer.Reset(wait)
+	}
+}
+
+// tick is called by the limiter's timer to dispatch tokens.
+func (l *Limiter[P]) tick() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dispatch()
+}
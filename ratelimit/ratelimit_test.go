@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLimiterPriorityOrder(t *testing.T) {
+	l := New[int](50, 1, func(x, y int) bool { return x < y })
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	priorities := []int{5, 3, 9, 1, 7}
+	released := make(chan int, len(priorities))
+	for _, p := range priorities {
+		go func(p int) {
+			if err := l.Wait(context.Background(), p); err != nil {
+				t.Error(err)
+			}
+			released <- p
+		}(p)
+	}
+	for l.Waiting() < len(priorities) {
+		time.Sleep(time.Millisecond)
+	}
+	// the first waiter may have been released before the others arrived
+	<-released
+	previous := -1
+	for i := 1; i < len(priorities); i++ {
+		p := <-released
+		if p < previous {
+			t.Fatalf("released priority %d after %d", p, previous)
+		}
+		previous = p
+	}
+}
+
+func TestLimiterArrivalOrder(t *testing.T) {
+	l := New[int](100, 1, func(x, y int) bool { return x < y })
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	const N = 8
+	released := make(chan int, N)
+	for i := 0; i < N; i++ {
+		go func(i int) {
+			if err := l.Wait(context.Background(), 0); err != nil {
+				t.Error(err)
+			}
+			released <- i
+		}(i)
+		// wait for the waiter to take its ticket before the next arrives
+		for {
+			l.mu.Lock()
+			arrived := l.next == uint64(i+1)
+			l.mu.Unlock()
+			if arrived {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for i := 0; i < N; i++ {
+		if j := <-released; j != i {
+			t.Fatalf("released waiter %d, expected %d", j, i)
+		}
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := New[int](1, 1, func(x, y int) bool { return x < y })
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, 0); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if n := l.Waiting(); n != 0 {
+		t.Fatalf("expected no waiters, got %d", n)
+	}
+	if n, _ := l.waiters.Size(); n != 0 {
+		t.Fatalf("expected the cancelled ticket to be deleted, got %d tickets", n)
+	}
+}
+
+func TestNewInvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		func() {
+			defer func() {
+				if r := recover(); r != ErrInvalidRate {
+					t.Fatalf("rate %v: expected panic %v, got %v", rate, ErrInvalidRate, r)
+				}
+			}()
+			New[int](rate, 1, func(x, y int) bool { return x < y })
+		}()
+	}
+}
@@ -0,0 +1,98 @@
+package fheap
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// ComparatorPanicError reports a panic raised by a heap's priority
+// comparison function.
+type ComparatorPanicError[P any] struct {
+	X, Y  P   // the priorities being compared
+	Value any // the value the comparison function panicked with
+}
+
+// Error describes the panic.
+func (e *ComparatorPanicError[P]) Error() string {
+	return fmt.Sprintf("comparison of priorities %v and %v panicked: %v", e.X, e.Y, e.Value)
+}
+
+// Unwrap returns the value the comparison function panicked with, if it
+// is an error.
+func (e *ComparatorPanicError[P]) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// guardComparator wraps a priority comparison function so that its panics
+// are raised as a *ComparatorPanicError.
+func guardComparator[P any](higherThan func(x, y P) bool) func(x, y P) bool {
+	return func(x, y P) bool {
+		defer func() {
+			if r := recover(); r != nil {
+				panic(&ComparatorPanicError[P]{X: x, Y: y, Value: r})
+			}
+		}()
+		return higherThan(x, y)
+	}
+}
+
+// recoverPanic recovers a panic raised during a heap operation by the
+// heap's comparison function or by the runtime, returning it through err.
+// Since the operation may have been interrupted midway, the heap is flagged
+// as corrupted, and further operations on it return an error wrapping
+// ErrCorrupted. The heap is likewise flagged if the operation failed on
+// corrupted node pointers. Panics raised by user callbacks called through
+// `callback` are propagated, as are panics other than runtime panics.
+func (fh *Heap[V, P]) recoverPanic(err *error) {
+	r := recover()
+	if r == nil {
+		fh.callbackPanicked = false
+		if fh.corrupted == nil && isNodeError(*err) {
+			fh.corrupted = *err
+		}
+		return
+	}
+	if fh.callbackPanicked {
+		// left set for the operations enclosing this one, if any
+		panic(r)
+	}
+	switch r := r.(type) {
+	case *ComparatorPanicError[P]:
+		fh.corrupted = fmt.Errorf("%w: %w", ErrCorrupted, r)
+		*err = r
+	case runtime.Error:
+		*err = fh.corrupt("%v", r)
+	default:
+		panic(r)
+	}
+}
+
+// callback calls f, which calls a user callback such as a predicate or a
+// hook, noting whether it panicked so that recoverPanic propagates the
+// panic. Callbacks are called this way where the heap is consistent, i.e.
+// before an operation modifies the heap or once it has.
+func (fh *Heap[V, P]) callback(f func()) {
+	returned := false
+	defer func() {
+		if !returned {
+			fh.callbackPanicked = true
+		}
+	}()
+	f()
+	returned = true
+}
+
+// corrupt flags the heap as corrupted, returning an error wrapping
+// ErrCorrupted with the given reason.
+func (fh *Heap[V, P]) corrupt(format string, args ...any) error {
+	fh.corrupted = fmt.Errorf("%w: %s", ErrCorrupted, fmt.Sprintf(format, args...))
+	return fh.corrupted
+}
+
+// isNodeError reports whether err arose from corrupted node pointers.
+func isNodeError(err error) bool {
+	return errors.Is(err, errNilFnode) || errors.Is(err, errBarrenFnode) ||
+		errors.Is(err, errUnrelatedFnode)
+}
@@ -0,0 +1,182 @@
+package fheap
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFHeapComparatorPanic(t *testing.T) {
+	poison := errors.New("poisoned priority")
+	h := New[int, int](func(x, y int) bool {
+		if x == 13 || y == 13 {
+			panic(poison)
+		}
+		return x < y
+	}, math.MinInt)
+	for i := 0; i < 10; i++ {
+		if err := h.Push(i, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := h.Push(13, 13)
+	var cpe *ComparatorPanicError[int]
+	if !errors.As(err, &cpe) {
+		t.Fatalf("expected a *ComparatorPanicError, got %v", err)
+	}
+	if cpe.X != 13 && cpe.Y != 13 {
+		t.Fatalf("expected priority 13 to be compared, got %v and %v", cpe.X, cpe.Y)
+	}
+	if !errors.Is(err, poison) {
+		t.Fatalf("expected %v to wrap %v", err, poison)
+	}
+	if _, err := h.Pop(); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("expected %v, got %v", ErrCorrupted, err)
+	}
+	if err := h.Push(20, 20); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("expected %v, got %v", ErrCorrupted, err)
+	}
+}
+
+func TestFHeapCorruptedPointers(t *testing.T) {
+	if debug {
+		t.Skip("corrupting the heap panics under fheapdebug")
+	}
+	corruptions := map[string]func(h *Heap[int, int]){
+		"nil sibling": func(h *Heap[int, int]) {
+			h.prioritaire.right.right = nil
+		},
+		"short-circuited root list": func(h *Heap[int, int]) {
+			r1 := h.prioritaire.right
+			r1.right.right = r1
+		},
+		"cyclic children": func(h *Heap[int, int]) {
+			h.prioritaire.children.left = h.prioritaire.children.right
+		},
+	}
+	for name, corrupt := range corruptions {
+		t.Run(name, func(t *testing.T) {
+			h := intMinHeap[int]()
+			for i := range 17 {
+				if err := Push(h, i, i, t.Name()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if name == "cyclic children" {
+				if _, err := Pop(h, t.Name()); err != nil {
+					t.Fatal(err)
+				}
+				if h.prioritaire.degree < 2 {
+					t.Fatalf("expected the top to have children, got degree %d", h.prioritaire.degree)
+				}
+			}
+			corrupt(h)
+			if _, err := h.Pop(); !errors.Is(err, ErrCorrupted) {
+				t.Fatalf("expected %v, got %v", ErrCorrupted, err)
+			}
+			if err := h.Push(17, 17); !errors.Is(err, ErrCorrupted) {
+				t.Fatalf("expected %v, got %v", ErrCorrupted, err)
+			}
+		})
+	}
+}
+
+func TestFHeapCallbackPanic(t *testing.T) {
+	h := intMinHeap[int]()
+	for i := range 8 {
+		if err := Push(h, i, i, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var empty []int
+	callbacks := map[string]func() error{
+		"DeleteWhere": func() error {
+			_, err := h.DeleteWhere(func(v, p int) bool { return empty[v] == p })
+			return err
+		},
+		"AdjustPriority": func() error {
+			return h.AdjustPriority(7, func(p int) int { return empty[p] })
+		},
+	}
+	for name, call := range callbacks {
+		t.Run(name, func(t *testing.T) {
+			func() {
+				defer func() {
+					if _, ok := recover().(runtime.Error); !ok {
+						t.Fatal("expected the callback's runtime panic to propagate")
+					}
+				}()
+				_ = call()
+			}()
+			if err := h.Push(-1, -1); err != nil {
+				t.Fatalf("expected the heap not to be flagged, got %v", err)
+			}
+			if _, err := h.Pop(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestFHeapHookPanic(t *testing.T) {
+	var empty []int
+	less := func(x, y int) bool { return x < y }
+	hooks := map[string]func() (*Heap[int, int], func() error){
+		"Interner": func() (*Heap[int, int], func() error) {
+			h := New(less, math.MinInt, WithInterner[int, int](func(v int) int {
+				if v < 0 {
+					return empty[v]
+				}
+				return v
+			}))
+			return h, func() error { return h.Push(-1, 0) }
+		},
+		"TTL": func() (*Heap[int, int], func() error) {
+			h := New(less, math.MinInt, WithTTL(time.Minute, func(v, _ int) { _ = empty[v] }))
+			now := time.Unix(0, 0)
+			h.expiry.now = func() time.Time { return now }
+			return h, func() error {
+				if err := h.Push(-1, -1); err != nil {
+					return err
+				}
+				now = now.Add(time.Hour)
+				_, err := h.Pop()
+				return err
+			}
+		},
+		"MemoryEviction": func() (*Heap[int, int], func() error) {
+			h := New(less, math.MinInt,
+				WithMemoryEviction[int, int](1000, 500, func(v, _ int) { _ = empty[v] }))
+			h.memory.readUsage = func() uint64 { return 2000 }
+			return h, func() error {
+				if err := h.Push(-1, -1); err != nil {
+					return err
+				}
+				// the next push evicts values from within Push
+				h.memory.pushes = memoryCheckInterval - 1
+				return h.Push(-2, -2)
+			}
+		},
+	}
+	for name, hook := range hooks {
+		t.Run(name, func(t *testing.T) {
+			h, call := hook()
+			func() {
+				defer func() {
+					if _, ok := recover().(runtime.Error); !ok {
+						t.Fatal("expected the hook's runtime panic to propagate")
+					}
+				}()
+				_ = call()
+			}()
+			if err := h.Push(1, 1); err != nil {
+				t.Fatalf("expected the heap not to be flagged, got %v", err)
+			}
+			if err := isFibonacciHeap(h); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package fheap
+
+// lookahead is the number of roots Pop inspects for a new prioritaire in
+// relaxed mode before falling back to consolidating the heap.
+const lookahead = 8
+
+// WithRelaxedOrdering allows Pop to return any element whose priority is
+// within some epsilon of the highest priority in the heap, trading exactness
+// for throughput. `within` reports whether `priority` is within epsilon of
+// `bound`, and must be monotonic, i.e. if a priority is within epsilon of
+// the bound, then so are all higher priorities.
+// After removing prioritaire, Pop looks ahead a few roots for one within
+// epsilon of a bound on the heap's highest priority, only consolidating the
+// heap if none is found.
+func WithRelaxedOrdering[V comparable, P any](within func(bound, priority P) bool) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.relaxation = &relaxation[P]{within: within}
+	}
+}
+
+// relaxation holds a relaxed heap's epsilon predicate, and a priority at
+// least as high as any priority in the heap.
+type relaxation[P any] struct {
+	within func(bound, priority P) bool
+	bound  P
+}
+
+// extendBound ensures the relaxation bound is at least as high as the given
+// priority, which is about to be added to the heap. Extending is a no-op
+// for heaps with exact ordering.
+func (fh *Heap[V, P]) extendBound(priority P) {
+	if fh.relaxation == nil {
+		return
+	}
+	if fh.prioritaire == nil || fh.higherThan(priority, fh.relaxation.bound) {
+		fh.relaxation.bound = priority
+	}
+}
+
+// selectRelaxed looks ahead a bounded number of roots for one whose
+// priority is within epsilon of the relaxation bound, making it
+// prioritaire if found.
+func (fh *Heap[V, P]) selectRelaxed() bool {
+	if fh.relaxation == nil {
+		return false
+	}
+	start := fh.prioritaire
+	root := start
+	for i := 0; i < lookahead; i++ {
+		if fh.relaxation.within(fh.relaxation.bound, root.priority) {
+			fh.prioritaire = root
+			return true
+		}
+		if root = root.right; root == start {
+			break
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapRelaxedOrdering(t *testing.T) {
+	const epsilon = 5
+	h := New[int, int](func(x, y int) bool { return x < y }, math.MinInt,
+		WithRelaxedOrdering[int, int](func(bound, p int) bool { return p-bound <= epsilon }))
+	N := *HeapSize
+	remaining := map[int]bool{}
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+		remaining[p] = true
+	}
+	for i := 0; i < N/4; i++ {
+		v := rand.Intn(N)
+		if remaining[v] {
+			if err := Delete(h, v, t.Name()); err != nil {
+				t.Fatal(err)
+			}
+			delete(remaining, v)
+		}
+	}
+	for len(remaining) > 0 {
+		best := N
+		for p := range remaining {
+			best = min(best, p)
+		}
+		v, err := Pop(h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := h.checkInvariants(); err != nil {
+			t.Fatal(err)
+		}
+		if !remaining[v] {
+			t.Fatalf("popped unexpected value=%d", v)
+		}
+		if v-best > epsilon {
+			t.Fatalf("popped value=%d, more than %d from best=%d", v, epsilon, best)
+		}
+		delete(remaining, v)
+	}
+	if _, err := h.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+}
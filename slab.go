@@ -0,0 +1,308 @@
+package fheap
+
+import (
+	"fmt"
+	"math"
+)
+
+// slabNil is the index standing for no node in a SlabHeap.
+const slabNil int32 = -1
+
+// slabNode is a SlabHeap node, whose links are indices into the heap's slab.
+type slabNode[V, P any] struct {
+	value                         V
+	priority                      P
+	parent, children, left, right int32
+	degree                        int32
+	bereaved                      bool
+}
+
+// SlabHeap is a Fibonacci heap storing its nodes in a slab, i.e. a single
+// slice, linking them by int32 indices rather than by pointers, so that
+// nodes are packed together in memory. When V and P hold no pointers
+// either, the garbage collector needn't trace the slab at all, whereas
+// pointer-holding values or priorities are traced as usual. The slots of
+// removed nodes are reused by later pushes. Like an OrderedHeap, it
+// reserves no priority. A SlabHeap holds at most math.MaxInt32 values.
+type SlabHeap[V comparable, P any] struct {
+	nodes      []slabNode[V, P]
+	free       []int32
+	top        int32
+	values     map[V]int32
+	higherThan func(x, y P) bool
+	scratch    []int32
+}
+
+// NewSlab creates an empty SlabHeap.
+// NewSlab panics with ErrNilComparator if `higherThan` is nil.
+func NewSlab[V comparable, P any](higherThan func(x, y P) bool) *SlabHeap[V, P] {
+	if higherThan == nil {
+		panic(ErrNilComparator)
+	}
+	return &SlabHeap[V, P]{top: slabNil, values: map[V]int32{}, higherThan: higherThan}
+}
+
+// Size returns the number of elements in the heap.
+func (sh *SlabHeap[V, P]) Size() (int, error) {
+	if sh == nil {
+		return 0, ErrNilHeap
+	}
+	return len(sh.values), nil
+}
+
+// Push inserts a given value with the supplied priority into the heap.
+func (sh *SlabHeap[V, P]) Push(value V, priority P) error {
+	if sh == nil {
+		return ErrNilHeap
+	}
+	if _, ok := sh.values[value]; ok {
+		return &OpError[V, P]{"Push", value, priority, ErrDuplicateValue}
+	}
+	x, err := sh.alloc(value, priority)
+	if err != nil {
+		return err
+	}
+	sh.values[value] = x
+	if sh.top == slabNil {
+		sh.top = x
+		return nil
+	}
+	sh.insertLeft(sh.top, x)
+	if sh.higherThan(priority, sh.nodes[sh.top].priority) {
+		sh.top = x
+	}
+	return nil
+}
+
+// Pop removes and returns the highest-priority element from the heap
+// after consolidating the heap.
+func (sh *SlabHeap[V, P]) Pop() (value V, err error) {
+	if sh == nil {
+		return value, ErrNilHeap
+	}
+	if sh.top == slabNil {
+		return value, ErrEmptyHeap
+	}
+	z := sh.top
+	value = sh.nodes[z].value
+	// foster out the top's children
+	for c := sh.nodes[z].children; c != slabNil; c = sh.nodes[z].children {
+		sh.removeChild(z, c)
+		sh.insertLeft(z, c)
+	}
+	delete(sh.values, value)
+	next := sh.nodes[z].right
+	sh.unlink(z)
+	sh.release(z)
+	if next == z {
+		sh.top = slabNil
+		return value, nil
+	}
+	sh.top = next
+	sh.consolidate()
+	return value, nil
+}
+
+// TryPop pops the highest-priority value from the heap, reporting whether
+// there was one to pop.
+func (sh *SlabHeap[V, P]) TryPop() (V, bool) {
+	value, err := sh.Pop()
+	return value, err == nil
+}
+
+// TryPeek returns the highest-priority value in the heap without removing
+// it, reporting whether there was one.
+func (sh *SlabHeap[V, P]) TryPeek() (value V, ok bool) {
+	if sh == nil || sh.top == slabNil {
+		return
+	}
+	return sh.nodes[sh.top].value, true
+}
+
+// IncreasePriority increases a value's priority in the heap, if present.
+func (sh *SlabHeap[V, P]) IncreasePriority(value V, priority P) error {
+	if sh == nil {
+		return ErrNilHeap
+	}
+	if sh.top == slabNil {
+		return ErrEmptyHeap
+	}
+	x, ok := sh.values[value]
+	if !ok {
+		return &OpError[V, P]{"IncreasePriority", value, priority, ErrValueNotFound}
+	}
+	if old := sh.nodes[x].priority; sh.higherThan(old, priority) {
+		return &OpError[V, P]{"IncreasePriority", value, priority, fmt.Errorf("%w %v", ErrLowerPriority, old)}
+	}
+	sh.nodes[x].priority = priority
+	if y := sh.nodes[x].parent; y != slabNil && sh.higherThan(priority, sh.nodes[y].priority) {
+		sh.detach(x)
+	}
+	if sh.higherThan(priority, sh.nodes[sh.top].priority) {
+		sh.top = x
+	}
+	return nil
+}
+
+// Delete deletes a value from the heap, if present, by moving its node to
+// the root list and popping it.
+func (sh *SlabHeap[V, P]) Delete(value V) error {
+	if sh == nil {
+		return ErrNilHeap
+	}
+	if sh.top == slabNil {
+		return ErrEmptyHeap
+	}
+	x, ok := sh.values[value]
+	if !ok {
+		return &OpError[V, P]{Op: "Delete", Value: value, Err: ErrValueNotFound}
+	}
+	if sh.nodes[x].parent != slabNil {
+		sh.detach(x)
+	}
+	sh.top = x
+	_, err := sh.Pop()
+	return err
+}
+
+// alloc stores a new node in the slab, reusing a free slot if there is one,
+// and returns its index.
+func (sh *SlabHeap[V, P]) alloc(value V, priority P) (int32, error) {
+	var x int32
+	if n := len(sh.free); n > 0 {
+		x, sh.free = sh.free[n-1], sh.free[:n-1]
+	} else {
+		if len(sh.nodes) == math.MaxInt32 {
+			return slabNil, ErrHeapFull
+		}
+		x = int32(len(sh.nodes))
+		sh.nodes = append(sh.nodes, slabNode[V, P]{})
+	}
+	sh.nodes[x] = slabNode[V, P]{
+		value:    value,
+		priority: priority,
+		parent:   slabNil,
+		children: slabNil,
+		left:     x,
+		right:    x,
+	}
+	return x, nil
+}
+
+// release frees a removed node's slot, zeroing it so as not to keep its
+// value and priority alive.
+func (sh *SlabHeap[V, P]) release(x int32) {
+	sh.nodes[x] = slabNode[V, P]{}
+	sh.free = append(sh.free, x)
+}
+
+// insertLeft inserts node x, which must be alone in its ring, to the left
+// of node at.
+func (sh *SlabHeap[V, P]) insertLeft(at, x int32) {
+	nodes := sh.nodes
+	left := nodes[at].left
+	nodes[left].right = x
+	nodes[x].left = left
+	nodes[x].right = at
+	nodes[at].left = x
+}
+
+// unlink removes node x from its ring, leaving it alone in its own.
+func (sh *SlabHeap[V, P]) unlink(x int32) {
+	nodes := sh.nodes
+	left, right := nodes[x].left, nodes[x].right
+	nodes[left].right = right
+	nodes[right].left = left
+	nodes[x].left, nodes[x].right = x, x
+}
+
+// addChild makes root y a child of x.
+func (sh *SlabHeap[V, P]) addChild(x, y int32) {
+	sh.unlink(y)
+	if c := sh.nodes[x].children; c == slabNil {
+		sh.nodes[x].children = y
+	} else {
+		sh.insertLeft(c, y)
+	}
+	sh.nodes[y].parent = x
+	sh.nodes[y].bereaved = false
+	sh.nodes[x].degree++
+}
+
+// removeChild removes child y from x, leaving y alone in its ring and
+// unmarked.
+func (sh *SlabHeap[V, P]) removeChild(x, y int32) {
+	if sh.nodes[x].children == y {
+		sh.nodes[x].children = sh.nodes[y].right
+		if sh.nodes[y].right == y {
+			sh.nodes[x].children = slabNil
+		}
+	}
+	sh.unlink(y)
+	sh.nodes[y].parent = slabNil
+	sh.nodes[y].bereaved = false
+	sh.nodes[x].degree--
+}
+
+// consolidate reduces the number of trees in the heap.
+func (sh *SlabHeap[V, P]) consolidate() {
+	size := degreeBound(len(sh.values)) + 1
+	if cap(sh.scratch) < size {
+		sh.scratch = make([]int32, size)
+	}
+	A := sh.scratch[:size]
+	for i := range A {
+		A[i] = slabNil
+	}
+	end := sh.nodes[sh.top].left
+	for w := sh.top; ; {
+		next := sh.nodes[w].right
+		x := w
+		d := sh.nodes[x].degree
+		for A[d] != slabNil {
+			y := A[d]
+			if sh.higherThan(sh.nodes[y].priority, sh.nodes[x].priority) {
+				x, y = y, x
+			}
+			sh.addChild(x, y)
+			A[d] = slabNil
+			d++
+		}
+		A[d] = x
+		if w == end {
+			break
+		}
+		w = next
+	}
+	// find the new top
+	sh.top = slabNil
+	for _, root := range A {
+		if root == slabNil {
+			continue
+		}
+		sh.unlink(root)
+		if sh.top == slabNil {
+			sh.top = root
+			continue
+		}
+		sh.insertLeft(sh.top, root)
+		if sh.higherThan(sh.nodes[root].priority, sh.nodes[sh.top].priority) {
+			sh.top = root
+		}
+	}
+}
+
+// detach cuts x from its parent, turning it into a root, and performs the
+// ancestral cascading cuts.
+func (sh *SlabHeap[V, P]) detach(x int32) {
+	for y := sh.nodes[x].parent; y != slabNil; x, y = y, sh.nodes[y].parent {
+		sh.removeChild(y, x)
+		sh.insertLeft(sh.top, x)
+		if !sh.nodes[y].bereaved {
+			if sh.nodes[y].parent != slabNil {
+				sh.nodes[y].bereaved = true
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package fheap
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+// checkSlab verifies a SlabHeap's structural invariants.
+func checkSlab[V comparable, P any](sh *SlabHeap[V, P]) error {
+	if sh.top == slabNil {
+		if len(sh.values) != 0 {
+			return fmt.Errorf("top=nil but %d values", len(sh.values))
+		}
+		return nil
+	}
+	seen := map[int32]bool{}
+	var visit func(ring, parent int32) error
+	visit = func(ring, parent int32) error {
+		for x := ring; ; {
+			n := sh.nodes[x]
+			if seen[x] {
+				return fmt.Errorf("node %v visited twice", n.value)
+			}
+			seen[x] = true
+			if sh.values[n.value] != x {
+				return fmt.Errorf("node %v isn't indexed by its value", n.value)
+			}
+			if sh.nodes[n.left].right != x || sh.nodes[n.right].left != x {
+				return fmt.Errorf("node %v has inconsistent siblings", n.value)
+			}
+			if n.parent != parent {
+				return fmt.Errorf("node %v has parent %d, expected %d", n.value, n.parent, parent)
+			}
+			if parent == slabNil && sh.higherThan(n.priority, sh.nodes[sh.top].priority) ||
+				parent != slabNil && sh.higherThan(n.priority, sh.nodes[parent].priority) {
+				return fmt.Errorf("node %v is higher than its parent or the top", n.value)
+			}
+			if n.children != slabNil {
+				degree := int32(0)
+				for c := n.children; ; c = sh.nodes[c].right {
+					if degree++; sh.nodes[c].right == n.children {
+						break
+					}
+				}
+				if degree != n.degree {
+					return fmt.Errorf("node %v has %d children, but degree=%d", n.value, degree, n.degree)
+				}
+				if err := visit(n.children, x); err != nil {
+					return err
+				}
+			} else if n.degree != 0 {
+				return fmt.Errorf("node %v has no children, but degree=%d", n.value, n.degree)
+			}
+			if x = n.right; x == ring {
+				return nil
+			}
+		}
+	}
+	if err := visit(sh.top, slabNil); err != nil {
+		return err
+	}
+	if len(seen) != len(sh.values) || len(seen)+len(sh.free) != len(sh.nodes) {
+		return fmt.Errorf("counted %d nodes and %d free slots, but %d values in %d slots",
+			len(seen), len(sh.free), len(sh.values), len(sh.nodes))
+	}
+	return nil
+}
+
+func TestSlabHeap(t *testing.T) {
+	h := NewSlab[int, int](func(x, y int) bool { return x < y })
+	if _, err := h.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p+N); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Push(0, 0); !errors.Is(err, ErrDuplicateValue) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateValue, err)
+	}
+	if v, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	} else if v != 0 {
+		t.Fatalf("expected value=0, got %d", v)
+	}
+	if err := h.IncreasePriority(N-1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.IncreasePriority(N-2, 3*N); !errors.Is(err, ErrLowerPriority) {
+		t.Fatalf("expected %v, got %v", ErrLowerPriority, err)
+	}
+	if err := h.Delete(N / 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSlab(h); err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{N - 1}
+	for v := 1; v < N-1; v++ {
+		if v != N/2 {
+			expected = append(expected, v)
+		}
+	}
+	for _, e := range expected {
+		if v, err := h.Pop(); err != nil {
+			t.Fatal(err)
+		} else if v != e {
+			t.Fatalf("expected value=%d, got %d", e, v)
+		}
+		if err := checkSlab(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s, _ := h.Size(); s != 0 {
+		t.Fatalf("expected empty heap, got size=%d", s)
+	}
+}
+
+func TestSlabHeap_Random(t *testing.T) {
+	h := NewSlab[int, int](func(x, y int) bool { return x > y })
+	ref := map[int]int{}
+	N := *HeapSize
+	for i := range 20 * N {
+		v := rand.Intn(N)
+		p, present := ref[v]
+		switch {
+		case !present:
+			p = rand.Intn(N)
+			if err := h.Push(v, p); err != nil {
+				t.Fatal(err)
+			}
+			ref[v] = p
+		case i%3 == 0:
+			if err := h.IncreasePriority(v, p+rand.Intn(N)); err != nil {
+				t.Fatal(err)
+			}
+			ref[v] = h.nodes[h.values[v]].priority
+		case i%3 == 1:
+			if err := h.Delete(v); err != nil {
+				t.Fatal(err)
+			}
+			delete(ref, v)
+		default:
+			top, ok := h.TryPop()
+			if !ok {
+				t.Fatal("expected a value to pop")
+			}
+			for _, q := range ref {
+				if q > ref[top] {
+					t.Fatalf("popped %d with priority %d, but %d remains", top, ref[top], q)
+				}
+			}
+			delete(ref, top)
+		}
+		if err := checkSlab(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, _ := h.Size(); n != len(ref) {
+		t.Fatalf("expected size=%d, got %d", len(ref), n)
+	}
+}
+
+func BenchmarkSlabHeapPushPop(b *testing.B) {
+	h := NewSlab[int, int](func(x, y int) bool { return x < y })
+	perm := rand.Perm(1 << 10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range perm {
+			h.Push(p, p)
+		}
+		for range perm {
+			h.Pop()
+		}
+	}
+}
+
+// benchmarkGC measures garbage collections while a heap of a million
+// values is live.
+func benchmarkGC(b *testing.B, push func(v, p int)) {
+	for _, p := range rand.Perm(1 << 20) {
+		push(p, p)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+	}
+}
+
+func BenchmarkFHeapGC(b *testing.B) {
+	h := intMinHeap[int]()
+	benchmarkGC(b, func(v, p int) { h.Push(v, p) })
+	runtime.KeepAlive(h)
+}
+
+func BenchmarkSlabHeapGC(b *testing.B) {
+	h := NewSlab[int, int](func(x, y int) bool { return x < y })
+	benchmarkGC(b, func(v, p int) { h.Push(v, p) })
+	runtime.KeepAlive(h)
+}
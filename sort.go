@@ -0,0 +1,32 @@
+package fheap
+
+// PartialSort returns the k smallest elements of s according to less, in
+// ascending order. It maintains a heap of the k smallest elements seen so
+// far, whose top is the largest of them.
+// Since the heap's operations can't fail, PartialSort panics if they do,
+// e.g. when less panics.
+func PartialSort[E any](s []E, k int, less func(x, y E) bool) []E {
+	k = min(k, len(s))
+	if k <= 0 {
+		return []E{}
+	}
+	h := NewUnreserved[int](func(x, y E) bool { return less(y, x) })
+	for i, e := range s {
+		if len(h.values) < k {
+			must(h.Push(i, e))
+			continue
+		}
+		if less(e, h.prioritaire.priority) {
+			_, err := h.Pop()
+			must(err)
+			must(h.Push(i, e))
+		}
+	}
+	sorted := make([]E, k)
+	for i := k - 1; i >= 0; i-- {
+		j, err := h.Pop()
+		must(err)
+		sorted[i] = s[j]
+	}
+	return sorted
+}
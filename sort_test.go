@@ -0,0 +1,29 @@
+package fheap
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestPartialSort(t *testing.T) {
+	less := func(x, y int) bool { return x < y }
+	N := *HeapSize
+	s := rand.Perm(N)
+	for _, k := range []int{-1, 0, 1, N / 3, N, 2 * N} {
+		expected := []int{}
+		for i := 0; i < min(max(k, 0), N); i++ {
+			expected = append(expected, i)
+		}
+		actual := PartialSort(s, k, less)
+		if !slices.Equal(actual, expected) {
+			t.Fatalf("[k=%d] expected %v, got %v", k, expected, actual)
+		}
+	}
+	words := []string{"pear", "fig", "apple", "fig", "kiwi"}
+	actual := fmt.Sprint(PartialSort(words, 3, func(x, y string) bool { return x < y }))
+	if expected := "[apple fig fig]"; actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+}
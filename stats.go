@@ -0,0 +1,131 @@
+package fheap
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Stats is a snapshot of a heap's structure.
+type Stats struct {
+	Size        int // number of values
+	Roots       int // number of trees in the root list
+	MaxDegree   int // highest number of children of any node
+	Marked      int // number of bereaved nodes
+	DegreeBound int // tracked upper bound on MaxDegree, sizing consolidations
+}
+
+// Stats returns a snapshot of the heap's structure, for monitoring its
+// health. It takes time linear in the heap's size.
+func (fh *Heap[V, P]) Stats() (Stats, error) {
+	if fh == nil {
+		return Stats{}, ErrNilHeap
+	}
+	stats := Stats{Size: fh.len(), Marked: fh.marked, DegreeBound: fh.maxDegree}
+	for x := range fh.nodes() {
+		stats.MaxDegree = max(stats.MaxDegree, x.degree)
+	}
+	if fh.prioritaire != nil {
+		for root := fh.prioritaire; ; root = root.right {
+			stats.Roots++
+			if root.right == fh.prioritaire {
+				break
+			}
+		}
+	}
+	return stats, nil
+}
+
+// TreeInfo describes a tree in a heap's root list.
+type TreeInfo[V, P any] struct {
+	Value    V   // the root's value
+	Priority P   // the root's priority
+	Degree   int // the root's number of children
+	Size     int // number of values in the tree
+	Depth    int // number of levels below the root
+}
+
+// Trees describes the trees in the heap's root list, starting with the
+// highest-priority value's.
+func (fh *Heap[V, P]) Trees() ([]TreeInfo[V, P], error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.prioritaire == nil {
+		return nil, nil
+	}
+	var trees []TreeInfo[V, P]
+	for root := fh.prioritaire; ; root = root.right {
+		info := TreeInfo[V, P]{Value: root.value, Priority: root.priority, Degree: root.degree}
+		type level struct {
+			x     *fnode[V, P]
+			depth int
+		}
+		// trees can be deep, so they're walked with an explicit stack
+		stack := []level{{root, 0}}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			info.Size++
+			info.Depth = max(info.Depth, top.depth)
+			if top.x.children == nil {
+				continue
+			}
+			for child := top.x.children; ; child = child.right {
+				stack = append(stack, level{child, top.depth + 1})
+				if child.right == top.x.children {
+					break
+				}
+			}
+		}
+		trees = append(trees, info)
+		if root.right == fh.prioritaire {
+			break
+		}
+	}
+	return trees, nil
+}
+
+// DegreeHistogram counts the heap's nodes by their number of children.
+func (fh *Heap[V, P]) DegreeHistogram() (map[int]int, error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	histogram := map[int]int{}
+	for x := range fh.nodes() {
+		histogram[x.degree]++
+	}
+	return histogram, nil
+}
+
+// PriorityQuantiles returns the heap's priorities at the given quantiles,
+// where quantile 0 is the highest priority and quantile 1 the lowest,
+// without modifying the heap. It takes O(n log n) time to sort the heap's
+// priorities.
+func (fh *Heap[V, P]) PriorityQuantiles(qs []float64) (quantiles []P, err error) {
+	if fh == nil {
+		return nil, ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return nil, fh.corrupted
+	}
+	if fh.prioritaire == nil {
+		return nil, ErrEmptyHeap
+	}
+	for _, q := range qs {
+		if !(q >= 0 && q <= 1) {
+			return nil, fmt.Errorf("quantile %v out of range [0, 1]", q)
+		}
+	}
+	defer fh.recoverPanic(&err)
+	nodes := make([]*fnode[V, P], 0, fh.len())
+	for x := range fh.nodes() {
+		nodes = append(nodes, x)
+	}
+	slices.SortFunc(nodes, fh.compareNodes)
+	quantiles = make([]P, len(qs))
+	for i, q := range qs {
+		quantiles[i] = nodes[int(math.Round(q*float64(len(nodes)-1)))].priority
+	}
+	return quantiles, nil
+}
@@ -0,0 +1,169 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFHeapStats(t *testing.T) {
+	h := intMinHeap[int]()
+	if stats, err := h.Stats(); err != nil {
+		t.Fatal(err)
+	} else if stats != (Stats{}) {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(h, p, p+N, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats, err := h.Stats(); err != nil {
+		t.Fatal(err)
+	} else if expected := (Stats{Size: N, Roots: N}); stats != expected {
+		t.Fatalf("expected %+v, got %+v", expected, stats)
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range rand.Perm(N)[:N/2] {
+		if v != 0 {
+			if err := IncreasePriority(h, v, v, t.Name()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	stats, err := h.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	marked := 0
+	for _, x := range h.values {
+		if x.bereaved {
+			marked++
+		}
+	}
+	if stats.Size != N-1 || stats.Marked != marked {
+		t.Fatalf("expected size=%d and marked=%d, got %+v", N-1, marked, stats)
+	}
+	if stats.MaxDegree > degreeBound(N) || stats.MaxDegree == 0 {
+		t.Fatalf("expected 0 < max degree <= %d, got %d", degreeBound(N), stats.MaxDegree)
+	}
+	if stats.Roots < 1 || stats.Roots > N/2+degreeBound(N)+1 {
+		t.Fatalf("unexpected root count %d", stats.Roots)
+	}
+	// the tracked bound survives cuts, but is reset once the heap empties
+	if stats.DegreeBound < stats.MaxDegree || stats.DegreeBound > degreeBound(N) {
+		t.Fatalf("expected max degree %d <= degree bound <= %d, got %d", stats.MaxDegree, degreeBound(N), stats.DegreeBound)
+	}
+	for range N - 1 {
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats, err := h.Stats(); err != nil {
+		t.Fatal(err)
+	} else if stats != (Stats{}) {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+	var nilHeap *Heap[int, int]
+	if _, err := nilHeap.Stats(); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+}
+
+func TestFHeapTrees(t *testing.T) {
+	h := intMinHeap[int]()
+	if trees, err := h.Trees(); err != nil || len(trees) != 0 {
+		t.Fatalf("expected no trees, got (%v, %v)", trees, err)
+	}
+	// popping 0 from 2^k+1 values leaves a binomial tree of order k
+	const k = 4
+	for v := range 1<<k + 1 {
+		if err := Push(h, v, v, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Pop(h, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	trees, err := h.Trees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := TreeInfo[int, int]{Value: 1, Priority: 1, Degree: k, Size: 1 << k, Depth: k}
+	if len(trees) != 1 || trees[0] != expected {
+		t.Fatalf("expected [%+v], got %+v", expected, trees)
+	}
+	N := *HeapSize
+	h = scrambledHeap(t, N)
+	trees, err = h.Trees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := h.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := 0
+	for i, tree := range trees {
+		size += tree.Size
+		if x := h.values[tree.Value]; x.parent != nil || x.degree != tree.Degree {
+			t.Fatalf("tree %d: %+v doesn't describe a root", i, tree)
+		}
+	}
+	if trees[0].Value != h.prioritaire.value || len(trees) != stats.Roots || size != N {
+		t.Fatalf("expected %d trees of %d values led by %d, got %+v", stats.Roots, N, h.prioritaire.value, trees)
+	}
+}
+
+func TestFHeapDegreeHistogram(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	histogram, err := h.DegreeHistogram()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, children := 0, 0
+	for degree, count := range histogram {
+		nodes += count
+		children += degree * count
+	}
+	trees, err := h.Trees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every node but the roots is some node's child
+	if nodes != N || children != N-len(trees) {
+		t.Fatalf("expected %d nodes with %d children, got %v", N, N-len(trees), histogram)
+	}
+}
+
+func TestFHeapPriorityQuantiles(t *testing.T) {
+	h := intMinHeap[int]()
+	if _, err := h.PriorityQuantiles([]float64{0.5}); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	for _, p := range rand.Perm(101) {
+		if err := Push(h, p, p, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	quantiles, err := h.PriorityQuantiles([]float64{0, 0.25, 0.5, 0.99, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int{0, 25, 50, 99, 100}; !slices.Equal(quantiles, expected) {
+		t.Fatalf("expected %v, got %v", expected, quantiles)
+	}
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := h.PriorityQuantiles([]float64{q}); err == nil {
+			t.Fatalf("expected quantile %v to be rejected", q)
+		}
+	}
+	if n := h.Len(); n != 101 {
+		t.Fatalf("expected size=101, got %d", n)
+	}
+}
@@ -0,0 +1,116 @@
+package fheap
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// SyncHeap wraps a heap so that it can be used by multiple goroutines at
+// once. Pushes onto a heap bounded by WithMaxSize with OverflowReject can
+// wait for room with PushWait rather than fail with ErrHeapFull.
+type SyncHeap[V comparable, P any] struct {
+	mu    sync.Mutex
+	inner *Heap[V, P]
+	freed chan struct{} // closed once values leave the heap, if waited on
+}
+
+// NewSync wraps a heap in a SyncHeap. The heap mustn't be used other than
+// through the SyncHeap afterwards.
+func NewSync[V comparable, P any](inner *Heap[V, P]) *SyncHeap[V, P] {
+	return &SyncHeap[V, P]{inner: inner}
+}
+
+// Size returns the number of elements in the heap.
+func (sh *SyncHeap[V, P]) Size() (int, error) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.inner.Size()
+}
+
+// TryPeek returns the highest-priority value in the heap without removing
+// it, reporting whether there was one.
+func (sh *SyncHeap[V, P]) TryPeek() (value V, ok bool) {
+	sh.update(func() error {
+		value, ok = sh.inner.TryPeek()
+		return nil
+	})
+	return value, ok
+}
+
+// Push inserts a given value with the supplied priority into the heap.
+func (sh *SyncHeap[V, P]) Push(value V, priority P) error {
+	return sh.update(func() error { return sh.inner.Push(value, priority) })
+}
+
+// PushWait inserts a given value with the supplied priority into the heap,
+// waiting for values to be popped, deleted or evicted while the heap is
+// full, or until the context is done, in which case the context's error is
+// returned. Waiting pushes aren't served in any particular order.
+func (sh *SyncHeap[V, P]) PushWait(ctx context.Context, value V, priority P) error {
+	for {
+		freed, err := sh.tryPush(value, priority)
+		if freed == nil {
+			return err
+		}
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// tryPush pushes a value onto the heap, returning a channel closed once
+// values leave the heap if it's full.
+func (sh *SyncHeap[V, P]) tryPush(value V, priority P) (freed <-chan struct{}, err error) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	err = sh.apply(func() error { return sh.inner.Push(value, priority) })
+	if !errors.Is(err, ErrHeapFull) {
+		return nil, err
+	}
+	if sh.freed == nil {
+		sh.freed = make(chan struct{})
+	}
+	return sh.freed, nil
+}
+
+// Pop removes and returns the highest-priority element from the heap.
+func (sh *SyncHeap[V, P]) Pop() (value V, err error) {
+	err = sh.update(func() error {
+		value, err = sh.inner.Pop()
+		return err
+	})
+	return value, err
+}
+
+// IncreasePriority increases a value's priority in the heap, if present.
+func (sh *SyncHeap[V, P]) IncreasePriority(value V, priority P) error {
+	return sh.update(func() error { return sh.inner.IncreasePriority(value, priority) })
+}
+
+// Delete deletes a value from the heap, if present.
+func (sh *SyncHeap[V, P]) Delete(value V) error {
+	return sh.update(func() error { return sh.inner.Delete(value) })
+}
+
+// update applies an operation to the wrapped heap under the lock.
+func (sh *SyncHeap[V, P]) update(op func() error) error {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.apply(op)
+}
+
+// apply applies an operation to the wrapped heap, whose lock must be held,
+// waking waiting pushes if values left the heap, including values expired
+// or evicted by the operation.
+func (sh *SyncHeap[V, P]) apply(op func() error) error {
+	n := sh.inner.Len()
+	err := op()
+	if sh.freed != nil && sh.inner.Len() < n {
+		close(sh.freed)
+		sh.freed = nil
+	}
+	return err
+}
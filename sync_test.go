@@ -0,0 +1,90 @@
+package fheap
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncHeap(t *testing.T) {
+	h := NewSync(intMinHeap[int]())
+	N := *HeapSize
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < N; i += 4 {
+				if err := h.Push(i, i); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if size, _ := h.Size(); size != N {
+		t.Fatalf("expected size=%d, got %d", N, size)
+	}
+	popped := make([][]int, 4)
+	for w := range popped {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < N/4; i++ {
+				v, err := h.Pop()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				popped[w] = append(popped[w], v)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, values := range popped {
+		for i := 1; i < len(values); i++ {
+			if values[i] < values[i-1] {
+				t.Fatalf("popped %d after %d", values[i], values[i-1])
+			}
+		}
+	}
+}
+
+func TestSyncHeap_PushWait(t *testing.T) {
+	h := NewSync(New(func(x, y int) bool { return x < y }, math.MinInt,
+		WithMaxSize[string, int](1, OverflowReject)))
+	if err := h.PushWait(context.Background(), "a", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Push("b", 1); err != ErrHeapFull {
+		t.Fatalf("expected %v, got %v", ErrHeapFull, err)
+	}
+	pushed := make(chan error)
+	go func() { pushed <- h.PushWait(context.Background(), "b", 1) }()
+	select {
+	case err := <-pushed:
+		t.Fatalf("expected PushWait to wait, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	if v, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	} else if v != "a" {
+		t.Fatalf("expected a, got %s", v)
+	}
+	if err := <-pushed; err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := h.TryPeek(); !ok || v != "b" {
+		t.Fatalf("expected b, got %q", v)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := h.PushWait(ctx, "c", 2); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if size, _ := h.Size(); size != 1 {
+		t.Fatalf("expected size=1, got %d", size)
+	}
+}
@@ -0,0 +1,177 @@
+package fheap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkInvariants verifies the heap's structural invariants, namely that:
+//   - trees are heap-ordered, and no root is higher than prioritaire, or
+//     than the relaxation bound for heaps with relaxed ordering
+//   - sibling rings are consistently doubly-linked
+//   - children point to their parent, and degrees match child counts
+//   - roots have no parent and aren't bereaved
+//   - every node is indexed by its value, and vice versa
+//   - the count of bereaved nodes is accurate
+//   - no node's degree exceeds the bound D(n) = floor(log_phi(n)), nor the
+//     heap's tracked degree bound
+func (fh *Heap[V, P]) checkInvariants() error {
+	if fh == nil {
+		return nil
+	}
+	if fh.prioritaire == nil {
+		if fh.len() == 0 {
+			return nil
+		}
+		return fmt.Errorf("prioritaire=nil but %d values", fh.len())
+	}
+	best := fh.prioritaire.priority
+	if fh.relaxation != nil {
+		best = fh.relaxation.bound
+	}
+	seen := map[*fnode[V, P]]bool{}
+	for root := fh.prioritaire; ; root = root.right {
+		if root.parent != nil {
+			return fmt.Errorf("root %v has parent %v", root.value, root.parent.value)
+		}
+		if root.bereaved {
+			return fmt.Errorf("root %v is bereaved", root.value)
+		}
+		if fh.higherThan(root.priority, best) {
+			return fmt.Errorf("root %v priority %v higher than best priority %v",
+				root.value, root.priority, best)
+		}
+		if err := fh.checkTree(root, seen); err != nil {
+			return err
+		}
+		if root.right == fh.prioritaire {
+			break
+		}
+	}
+	if len(seen) != fh.len() {
+		return fmt.Errorf("counted %d nodes, but %d values", len(seen), fh.len())
+	}
+	marked, bound := 0, degreeBound(fh.len())
+	for n := range seen {
+		if n.bereaved {
+			marked++
+		}
+		if n.degree > bound {
+			return fmt.Errorf("node %v has degree %d, exceeding D(%d)=%d", n.value, n.degree, fh.len(), bound)
+		}
+		if n.degree > fh.maxDegree {
+			return fmt.Errorf("node %v has degree %d, exceeding the tracked bound %d", n.value, n.degree, fh.maxDegree)
+		}
+	}
+	if marked != fh.marked {
+		return fmt.Errorf("counted %d bereaved nodes, but expected %d", marked, fh.marked)
+	}
+	return nil
+}
+
+// Validate verifies the heap's structural invariants, returning an error
+// wrapping ErrCorrupted describing the first violation found. It checks
+// that trees are heap-ordered, that parent, child and sibling links are
+// consistent, that degrees match child counts, that roots aren't
+// bereaved, that the value index agrees with the nodes, and that no
+// node's degree exceeds the Fibonacci heap degree bound. It takes time
+// linear in the heap's size.
+func (fh *Heap[V, P]) Validate() (err error) {
+	if fh == nil {
+		return ErrNilHeap
+	}
+	if fh.corrupted != nil {
+		return fh.corrupted
+	}
+	defer fh.recoverPanic(&err)
+	if violation := fh.checkInvariants(); violation != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, violation)
+	}
+	return nil
+}
+
+// checkTree verifies the invariants of the tree rooted at n, recording
+// visited nodes in seen.
+func (fh *Heap[V, P]) checkTree(n *fnode[V, P], seen map[*fnode[V, P]]bool) error {
+	if n == nil {
+		return errNilFnode
+	}
+	if seen[n] {
+		return fmt.Errorf("node %v visited twice", n.value)
+	}
+	seen[n] = true
+	if !fh.unindexed && !fh.indexes(n) {
+		return fmt.Errorf("node %v isn't indexed by its value", n.value)
+	}
+	if n.left == nil || n.right == nil || n.left.right != n || n.right.left != n {
+		return fmt.Errorf("node %v has inconsistent siblings", n.value)
+	}
+	numChildren := 0
+	if n.children != nil {
+		for child := n.children; ; child = child.right {
+			if child.parent != n {
+				return fmt.Errorf("child %v of %v has parent %v", child.value, n.value, child.parent)
+			}
+			if fh.nodeHigherThan(child, n) {
+				return fmt.Errorf("parent (v=%v) priority %v lower than child's (v=%v) %v",
+					n.value, n.priority, child.value, child.priority)
+			}
+			if err := fh.checkTree(child, seen); err != nil {
+				return err
+			}
+			numChildren++
+			if child.right == n.children {
+				break
+			}
+		}
+	}
+	if numChildren != n.degree {
+		return fmt.Errorf("node %v has %d children, but degree=%d", n.value, numChildren, n.degree)
+	}
+	return nil
+}
+
+// dump renders the heap's trees, one node per line, indented by depth.
+func (fh *Heap[V, P]) dump() string {
+	var b strings.Builder
+	if fh == nil {
+		return "<nil heap>\n"
+	}
+	fmt.Fprintf(&b, "heap with %d values\n", fh.len())
+	dumpRing(&b, fh.prioritaire, 0, map[*fnode[V, P]]bool{})
+	return b.String()
+}
+
+// dumpRing renders a ring of siblings and their descendants, skipping
+// nodes that were already rendered.
+func dumpRing[V comparable, P any](b *strings.Builder, start *fnode[V, P], depth int, seen map[*fnode[V, P]]bool) {
+	for n := start; n != nil && !seen[n]; n = n.right {
+		dumpTree(b, n, depth, seen)
+	}
+}
+
+// dumpTree renders a node and its descendants, skipping nodes that were
+// already rendered.
+func dumpTree[V comparable, P any](b *strings.Builder, n *fnode[V, P], depth int, seen map[*fnode[V, P]]bool) {
+	seen[n] = true
+	fmt.Fprintf(b, "%s%v (priority=%v, degree=%d, bereaved=%t)\n",
+		strings.Repeat("  ", depth), n.value, n.priority, n.degree, n.bereaved)
+	dumpRing(b, n.children, depth+1, seen)
+}
+
+// assertInvariants panics with a structural dump if the heap's invariants
+// are violated. It is a no-op unless built with the fheapdebug tag.
+// Comparison function panics flag the heap as corrupted, as they would
+// during an operation.
+func (fh *Heap[V, P]) assertInvariants() {
+	if !debug || fh == nil || fh.corrupted != nil {
+		return
+	}
+	// the operation being checked may be propagating a callback's panic,
+	// which must outlive this check
+	defer func(panicked bool) { fh.callbackPanicked = panicked }(fh.callbackPanicked)
+	defer fh.recoverPanic(new(error))
+	if err := fh.checkInvariants(); err != nil {
+		panic(fmt.Sprintf("fheap: %v\n%s", err, fh.dump()))
+	}
+}
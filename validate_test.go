@@ -0,0 +1,105 @@
+package fheap
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFHeapCheckInvariants(t *testing.T) {
+	h := intMinHeap[int]()
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := h.Push(p, p); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.checkInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < N/2; i++ {
+		if _, err := h.Pop(); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.checkInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var parent *fnode[int, int]
+	for _, n := range h.values {
+		if n.children != nil {
+			parent = n
+			break
+		}
+	}
+	if parent == nil {
+		t.Fatal("expected a node with children")
+	}
+	parent.degree++
+	if err := h.checkInvariants(); err == nil {
+		t.Fatal("expected degree mismatch to be detected")
+	}
+	parent.degree--
+	parent.children.priority = parent.priority - 1
+	if err := h.checkInvariants(); err == nil {
+		t.Fatal("expected heap-order violation to be detected")
+	}
+}
+
+func TestFHeapDump(t *testing.T) {
+	h := intMinHeap[string]()
+	for i, v := range []string{"a", "b", "c", "d", "e"} {
+		if err := h.Push(v, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := h.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	dump := h.dump()
+	for _, v := range []string{"b", "c", "d", "e"} {
+		if !strings.Contains(dump, v+" (priority=") {
+			t.Fatalf("expected dump to contain %q, got\n%s", v, dump)
+		}
+	}
+	if !strings.Contains(dump, "\n  ") {
+		t.Fatalf("expected dump to contain indented children, got\n%s", dump)
+	}
+}
+
+func TestFHeapValidate(t *testing.T) {
+	N := *HeapSize
+	h := scrambledHeap(t, N)
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var nilHeap *Heap[int, int]
+	if err := nilHeap.Validate(); err != ErrNilHeap {
+		t.Fatalf("expected %v, got %v", ErrNilHeap, err)
+	}
+	cases := []struct {
+		name    string
+		corrupt func(h *Heap[int, int])
+		problem string
+	}{
+		{"bereaved count", func(h *Heap[int, int]) { h.marked++ }, "bereaved"},
+		{"index", func(h *Heap[int, int]) { delete(h.values, h.prioritaire.value) }, "indexed"},
+		{"degree bound", func(h *Heap[int, int]) {
+			for i := range 64 {
+				child := newFnode(-i-1, h.prioritaire.priority+1)
+				h.prioritaire.insertChild(child)
+				h.values[child.value] = child
+			}
+		}, "exceeding"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := scrambledHeap(t, N)
+			c.corrupt(h)
+			if err := h.Validate(); !errors.Is(err, ErrCorrupted) || !strings.Contains(err.Error(), c.problem) {
+				t.Fatalf("expected %v about %q, got %v", ErrCorrupted, c.problem, err)
+			}
+		})
+	}
+}
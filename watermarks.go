@@ -0,0 +1,40 @@
+package fheap
+
+// WithWatermarks registers callbacks invoked with the heap's size when it
+// rises to `high`, and when it subsequently falls back to `low`. Callbacks
+// are invoked synchronously by the operation changing the heap's size, so
+// they must not modify the heap, and their runtime panics flag the heap as
+// corrupted. `low` should be lower than `high`.
+func WithWatermarks[V comparable, P any](low, high int, onHigh, onLow func(size int)) Option[V, P] {
+	return func(fh *Heap[V, P]) {
+		fh.watermarks = &watermarks{low: low, high: high, onHigh: onHigh, onLow: onLow}
+	}
+}
+
+// watermarks tracks whether a heap's size has crossed its high watermark
+// without having since fallen back to its low watermark.
+type watermarks struct {
+	low, high     int
+	onHigh, onLow func(size int)
+	above         bool
+}
+
+// observe notifies the watermarks of the heap's new size. Observing is a
+// no-op if watermarks aren't enabled.
+func (w *watermarks) observe(size int) {
+	if w == nil {
+		return
+	}
+	switch {
+	case !w.above && size >= w.high:
+		w.above = true
+		if w.onHigh != nil {
+			w.onHigh(size)
+		}
+	case w.above && size <= w.low:
+		w.above = false
+		if w.onLow != nil {
+			w.onLow(size)
+		}
+	}
+}
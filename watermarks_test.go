@@ -0,0 +1,38 @@
+package fheap
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestFHeapWatermarks(t *testing.T) {
+	var events []string
+	h := New[int, int](func(x, y int) bool { return x < y }, math.MinInt,
+		WithWatermarks[int, int](2, 5,
+			func(size int) { events = append(events, fmt.Sprintf("high@%d", size)) },
+			func(size int) { events = append(events, fmt.Sprintf("low@%d", size)) }))
+	for i := 0; i < 6; i++ {
+		if err := Push(h, i, i, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := Pop(h, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Push(h, 10, 10, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(h, 10, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(h, 5, t.Name()); err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprint([]string{"high@5", "low@2"})
+	if actual := fmt.Sprint(events); actual != expected {
+		t.Fatalf("expected events %s, got %s", expected, actual)
+	}
+}
@@ -0,0 +1,107 @@
+package fheap
+
+import "weak"
+
+// WeakHeap is a Fibonacci heap holding its values weakly, so that the heap
+// doesn't keep otherwise unreachable values alive. Entries whose values
+// have been garbage collected are treated as deleted: they are skipped by
+// `Pop` and `TryPeek`, and purged lazily.
+type WeakHeap[T, P any] struct {
+	inner *Heap[weak.Pointer[T], P]
+}
+
+// NewWeak creates an empty WeakHeap. Its arguments are as for New.
+func NewWeak[T, P any](higherThan func(x, y P) bool, highestPriority P, opts ...Option[weak.Pointer[T], P]) *WeakHeap[T, P] {
+	return &WeakHeap[T, P]{inner: New(higherThan, highestPriority, opts...)}
+}
+
+// Size returns the number of elements in the heap, including those whose
+// values were collected but have yet to be purged.
+func (wh *WeakHeap[T, P]) Size() (int, error) {
+	if wh == nil {
+		return 0, ErrNilHeap
+	}
+	return wh.inner.Size()
+}
+
+// Push inserts a given value with the supplied priority into the heap.
+func (wh *WeakHeap[T, P]) Push(value *T, priority P) error {
+	if wh == nil {
+		return ErrNilHeap
+	}
+	return wh.inner.Push(weak.Make(value), priority)
+}
+
+// Pop removes and returns the highest-priority value from the heap which
+// is still alive, discarding collected values along the way.
+func (wh *WeakHeap[T, P]) Pop() (*T, error) {
+	if wh == nil {
+		return nil, ErrNilHeap
+	}
+	for {
+		ptr, err := wh.inner.Pop()
+		if err != nil {
+			return nil, err
+		}
+		if value := ptr.Value(); value != nil {
+			return value, nil
+		}
+	}
+}
+
+// TryPeek returns the highest-priority value in the heap which is still
+// alive without removing it, reporting whether there was one. Collected
+// values at the top of the heap are discarded.
+func (wh *WeakHeap[T, P]) TryPeek() (*T, bool) {
+	if wh == nil {
+		return nil, false
+	}
+	for {
+		ptr, ok := wh.inner.TryPeek()
+		if !ok {
+			return nil, false
+		}
+		if value := ptr.Value(); value != nil {
+			return value, true
+		}
+		if _, err := wh.inner.Pop(); err != nil {
+			return nil, false
+		}
+	}
+}
+
+// IncreasePriority increases a value's priority in the heap, if present.
+func (wh *WeakHeap[T, P]) IncreasePriority(value *T, priority P) error {
+	if wh == nil {
+		return ErrNilHeap
+	}
+	return wh.inner.IncreasePriority(weak.Make(value), priority)
+}
+
+// Delete deletes a value from the heap, if present.
+func (wh *WeakHeap[T, P]) Delete(value *T) error {
+	if wh == nil {
+		return ErrNilHeap
+	}
+	return wh.inner.Delete(weak.Make(value))
+}
+
+// Purge deletes every entry whose value has been collected, returning how
+// many entries were purged.
+func (wh *WeakHeap[T, P]) Purge() (int, error) {
+	if wh == nil {
+		return 0, ErrNilHeap
+	}
+	var dead []weak.Pointer[T]
+	for node := range wh.inner.nodes() {
+		if node.value.Value() == nil {
+			dead = append(dead, node.value)
+		}
+	}
+	for _, ptr := range dead {
+		if err := wh.inner.Delete(ptr); err != nil {
+			return 0, err
+		}
+	}
+	return len(dead), nil
+}
@@ -0,0 +1,57 @@
+package fheap
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+type payload struct {
+	id   int
+	data [1 << 10]byte
+}
+
+func TestWeakHeap(t *testing.T) {
+	h := NewWeak[payload, int](func(x, y int) bool { return x < y }, math.MinInt)
+	N := *HeapSize
+	alive := make([]*payload, 0, N/2)
+	for i := 0; i < N; i++ {
+		p := &payload{id: i}
+		if err := h.Push(p, i); err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 1 {
+			alive = append(alive, p)
+		}
+	}
+	runtime.GC()
+	if v, ok := h.TryPeek(); !ok || v.id != 1 {
+		t.Fatalf("expected to peek id=1, got %v, %t", v, ok)
+	}
+	purged, err := h.Purge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != N/2-1 {
+		t.Fatalf("expected %d purged entries, got %d", N/2-1, purged)
+	}
+	if err := h.Delete(alive[1]); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range alive {
+		if i == 1 {
+			continue
+		}
+		v, err := h.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != p {
+			t.Fatalf("expected id=%d, got %d", p.id, v.id)
+		}
+	}
+	if _, err := h.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	runtime.KeepAlive(alive)
+}
@@ -0,0 +1,70 @@
+package fheap
+
+import (
+	"cmp"
+	"reflect"
+	"unsafe"
+)
+
+// lazyInit initialises a zero-value heap on first use. Its comparator
+// orders priorities from lowest to highest, which requires P's underlying
+// type to be ordered; ErrNilComparator is returned otherwise.
+func (fh *Heap[V, P]) lazyInit() error {
+	if fh.higherThan == nil {
+		lessThan := orderedLessThan[P]()
+		if lessThan == nil {
+			return ErrNilComparator
+		}
+		fh.higherThan = guardComparator(lessThan)
+	}
+	if fh.values == nil && !fh.unindexed {
+		fh.values = map[V]*fnode[V, P]{}
+	}
+	return nil
+}
+
+// orderedLessThan returns a function reporting if x < y, as by cmp.Less,
+// for priority types whose underlying type is ordered, and nil otherwise.
+// P's kind is looked up once, and the function returned for it reads
+// priorities as their underlying type directly.
+func orderedLessThan[P any]() func(x, y P) bool {
+	switch reflect.TypeFor[P]().Kind() {
+	case reflect.Int:
+		return lessThanAs[P, int]()
+	case reflect.Int8:
+		return lessThanAs[P, int8]()
+	case reflect.Int16:
+		return lessThanAs[P, int16]()
+	case reflect.Int32:
+		return lessThanAs[P, int32]()
+	case reflect.Int64:
+		return lessThanAs[P, int64]()
+	case reflect.Uint:
+		return lessThanAs[P, uint]()
+	case reflect.Uint8:
+		return lessThanAs[P, uint8]()
+	case reflect.Uint16:
+		return lessThanAs[P, uint16]()
+	case reflect.Uint32:
+		return lessThanAs[P, uint32]()
+	case reflect.Uint64:
+		return lessThanAs[P, uint64]()
+	case reflect.Uintptr:
+		return lessThanAs[P, uintptr]()
+	case reflect.Float32:
+		return lessThanAs[P, float32]()
+	case reflect.Float64:
+		return lessThanAs[P, float64]()
+	case reflect.String:
+		return lessThanAs[P, string]()
+	}
+	return nil
+}
+
+// lessThanAs returns a function comparing priorities as by cmp.Less on
+// their underlying type T, which P's kind must match.
+func lessThanAs[P any, T cmp.Ordered]() func(x, y P) bool {
+	return func(x, y P) bool {
+		return cmp.Less(*(*T)(unsafe.Pointer(&x)), *(*T)(unsafe.Pointer(&y)))
+	}
+}
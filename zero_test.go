@@ -0,0 +1,66 @@
+package fheap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFHeapZeroValue(t *testing.T) {
+	type deadline float64
+	var h Heap[string, deadline]
+	if _, err := h.Pop(); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+	N := *HeapSize
+	for _, p := range rand.Perm(N) {
+		if err := Push(&h, string(rune(p)), deadline(p)-deadline(N)/2, t.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for expected := 0; expected < N; expected++ {
+		v, err := Pop(&h, t.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != string(rune(expected)) {
+			t.Fatalf("expected %q, got %q", string(rune(expected)), v)
+		}
+	}
+	if err := h.Delete("a"); err != ErrEmptyHeap {
+		t.Fatalf("expected %v, got %v", ErrEmptyHeap, err)
+	}
+}
+
+func TestFHeapZeroValueUnordered(t *testing.T) {
+	var h Heap[int, struct{}]
+	if err := h.Push(0, struct{}{}); err != ErrNilComparator {
+		t.Fatalf("expected %v, got %v", ErrNilComparator, err)
+	}
+	if err := h.PushSet(struct{}{}, 0, 1); err != ErrNilComparator {
+		t.Fatalf("expected %v, got %v", ErrNilComparator, err)
+	}
+}
+
+func TestOrderedLessThan(t *testing.T) {
+	if !orderedLessThan[int8]()(-1, 1) || orderedLessThan[int8]()(1, -1) {
+		t.Fatal("int8 misordered")
+	}
+	if !orderedLessThan[uint]()(1, 2) || orderedLessThan[uint]()(2, 1) {
+		t.Fatal("uint misordered")
+	}
+	if !orderedLessThan[string]()("a", "b") || orderedLessThan[string]()("b", "a") {
+		t.Fatal("string misordered")
+	}
+	type score float64
+	lessThan := orderedLessThan[score]()
+	if !lessThan(score(math.NaN()), -1) || !lessThan(-1, 1) || lessThan(1, -1) {
+		t.Fatal("named float misordered")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { lessThan(1, 2) }); allocs != 0 {
+		t.Fatalf("expected comparisons not to allocate, got %v allocations", allocs)
+	}
+	if orderedLessThan[any]() != nil {
+		t.Fatal("expected no comparator for interface priorities")
+	}
+}